@@ -0,0 +1,104 @@
+package enigma
+
+import "fmt"
+
+// maxCompiledStates is the most rotor and reflector positions Compile caches a permutation for, 26^4 needing 12MB
+const maxCompiledStates = 26 * 26 * 26 * 26
+
+// permutationCache holds the permutation of the whole machine, plugboard to plugboard, for every position of the
+// rotors and, when within maxCompiledStates, of the reflector
+type permutationCache struct {
+	permutations [][26]uint8
+	// reflector is the only reflector position cached, or -1 when all positions are
+	reflector int
+}
+
+// positionTables precomputes the traversal of a wheel in each of its 26 positions, forwards and backwards as
+// Rotor.traverse, so that the fast path needs no modular arithmetic
+func positionTables(connections *[2][26]int) *[2][26][26]uint8 {
+	t := &[2][26][26]uint8{}
+	for p := 0; p < 26; p++ {
+		for i := 0; i < 26; i++ {
+			t[0][p][i] = uint8((connections[0][(i+p)%26] - p + 52) % 26)
+			t[1][p][i] = uint8((connections[1][(i+p)%26] - p + 52) % 26)
+		}
+	}
+	return t
+}
+
+// Compile caches the permutation of the whole machine for every position of its rotors, so that each key press is
+// a single lookup after stepping. It is worthwhile for bulk encryption, taking a moment and up to 12MB for a
+// three or four rotor machine, and fails for more rotors. The reflector positions are cached as well for three
+// rotors, otherwise only the current one is and others fall back to the uncompiled path. Clones share the cache
+func (e *Enigma) Compile() error {
+	states := 1
+	for range e.rotors {
+		states *= 26
+	}
+	if states > maxCompiledStates {
+		return fmt.Errorf("%w: %d rotors", ErrTooManyStates, len(e.rotors))
+	}
+	c := &permutationCache{reflector: e.reflector.position}
+	if states*26 <= maxCompiledStates {
+		states *= 26
+		c.reflector = -1
+	}
+	c.permutations = make([][26]uint8, states)
+	m := e.Clone()
+	for i := range c.permutations {
+		s := i
+		if c.reflector < 0 {
+			m.reflector.position = s % 26
+			s /= 26
+		}
+		for _, r := range m.rotors {
+			r.position = s % 26
+			s /= 26
+		}
+		for in := range c.permutations[i] {
+			c.permutations[i][in] = uint8(m.traverseTables(in))
+		}
+	}
+	e.compiled = c
+	return nil
+}
+
+// permutation returns the cached permutation for the current positions of the machine, or nil if not cached
+func (c *permutationCache) permutation(e *Enigma) *[26]uint8 {
+	i := 0
+	for j := len(e.rotors) - 1; j >= 0; j-- {
+		i = i*26 + e.rotors[j].position
+	}
+	if c.reflector < 0 {
+		i = i*26 + e.reflector.position
+	} else if e.reflector.position != c.reflector {
+		return nil
+	}
+	return &c.permutations[i]
+}
+
+// permute passes a signal from the keyboard to the lamps for the current positions, with the compiled permutation
+// when there is one and otherwise with the position tables of each wheel
+func (e *Enigma) permute(in int) int {
+	if e.compiled != nil {
+		if p := e.compiled.permutation(e); p != nil {
+			return int(p[in])
+		}
+	}
+	return e.traverseTables(in)
+}
+
+// traverseTables passes a signal along the same path as encodeTrace, with array lookups in place of traverse
+func (e *Enigma) traverseTables(in int) int {
+	out := e.plugs.table[in]
+	out = e.entry.tables[1][e.entry.position][out]
+	for _, r := range e.rotors {
+		out = r.tables[0][r.position][out]
+	}
+	out = e.reflector.tables[0][e.reflector.position][out]
+	for i := e.rotorCount; i >= 0; i-- {
+		out = e.rotors[i].tables[1][e.rotors[i].position][out]
+	}
+	out = e.entry.tables[0][e.entry.position][out]
+	return int(e.plugs.table[out])
+}
@@ -0,0 +1,95 @@
+package enigma
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func compileTestMachines(t testing.TB) map[string]*Enigma {
+	m3, err := ParseKey("B II-IV-V 02-21-12 BLA AV BS CG DL FU HZ IN KM OW RX")
+	assert.Nil(t, err)
+	e3, err := m3.Enigma()
+	assert.Nil(t, err)
+	m4, err := NewM4([]*RotorConfiguration{
+		{Name: RotorI, Position: 0, RingSetting: 21},
+		{Name: RotorIV, Position: 13},
+		{Name: RotorII, Position: 9},
+		{Name: RotorBeta, Position: 21},
+	}, ReflectorBThin, "AT BL DF GJ HM NW OP QY RZ VX")
+	assert.Nil(t, err)
+	g, err := NewFromModel(ModelG, []*RotorConfiguration{
+		{Name: RotorGIII, Position: 25, RingSetting: 5},
+		{Name: RotorGII, Position: 24},
+		{Name: RotorGI, Position: 1, RingSetting: 3},
+	}, ReflectorG, "", WithReflectorPosition(4))
+	assert.Nil(t, err)
+	return map[string]*Enigma{"m3": e3, "m4": m4, "g": g}
+}
+
+func TestFastPathMatchesReference(t *testing.T) {
+	for name, e := range compileTestMachines(t) {
+		for _, compiled := range []bool{false, true} {
+			reference := e.Clone()
+			fast := e.Clone()
+			if compiled {
+				assert.Nil(t, fast.Compile())
+			}
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 20000; i++ {
+				r := rune(rnd.Intn(26)) + runeOffset
+				assert.Equal(t, reference.encodeTrace(r, nil), fast.encode(r), "%s compiled %v should match at %d", name, compiled, i)
+			}
+			assert.Equal(t, reference.Snapshot(), fast.Snapshot())
+		}
+	}
+}
+
+func TestCompileReflectorPosition(t *testing.T) {
+	e := compileTestMachines(t)["m4"]
+	assert.Nil(t, e.Compile())
+	assert.Equal(t, 0, e.compiled.reflector, "only the current reflector position should be cached for four rotors")
+	reference := e.Clone()
+	s := e.Snapshot()
+	s.Reflector = 5
+	assert.Nil(t, e.Restore(s))
+	assert.Nil(t, reference.Restore(s))
+	res, err := e.Encode("AAAAAAAAAA")
+	assert.Nil(t, err)
+	expected, err := reference.Encode("AAAAAAAAAA")
+	assert.Nil(t, err)
+	assert.Equal(t, expected, res, "uncached reflector positions should fall back")
+}
+
+func TestCompileTooManyRotors(t *testing.T) {
+	e, err := New([]*RotorConfiguration{{Name: RotorI}, {Name: RotorII}, {Name: RotorIII}, {Name: RotorIV}, {Name: RotorV}}, ReflectorB, "")
+	assert.Nil(t, err)
+	err = e.Compile()
+	assert.True(t, errors.Is(err, ErrTooManyStates))
+	assert.Nil(t, e.compiled)
+}
+
+func benchmarkEncode(b *testing.B, encode func(*Enigma, rune) rune, compile bool) {
+	e := compileTestMachines(b)["m3"]
+	if compile {
+		assert.Nil(b, e.Compile())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encode(e, rune(i%26)+runeOffset)
+	}
+}
+
+func BenchmarkEncodeReference(b *testing.B) {
+	benchmarkEncode(b, func(e *Enigma, r rune) rune { return e.encodeTrace(r, nil) }, false)
+}
+
+func BenchmarkEncodeTables(b *testing.B) {
+	benchmarkEncode(b, (*Enigma).encode, false)
+}
+
+func BenchmarkEncodeCompiled(b *testing.B) {
+	benchmarkEncode(b, (*Enigma).encode, true)
+}
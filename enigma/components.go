@@ -1,8 +1,9 @@
 package enigma
 
-import (
-	"fmt"
-)
+import "sync"
+
+// registryMu guards the rotor and reflector registries, which may be extended with RegisterRotor and RegisterReflector
+var registryMu sync.RWMutex
 
 // Rotors Names of the rotors to be used to specify conveniently
 const RotorI = "RotorI"
@@ -15,6 +16,23 @@ const RotorVII = "RotorVII"
 const RotorVIII = "RotorVIII"
 const RotorBeta = "RotorBeta"
 const RotorGamma = "RotorGamma"
+const RotorDI = "RotorDI"
+const RotorDII = "RotorDII"
+const RotorDIII = "RotorDIII"
+const RotorKI = "RotorKI"
+const RotorKII = "RotorKII"
+const RotorKIII = "RotorKIII"
+const RotorTI = "RotorTI"
+const RotorTII = "RotorTII"
+const RotorTIII = "RotorTIII"
+const RotorTIV = "RotorTIV"
+const RotorTV = "RotorTV"
+const RotorTVI = "RotorTVI"
+const RotorTVII = "RotorTVII"
+const RotorTVIII = "RotorTVIII"
+const RotorGI = "RotorGI"
+const RotorGII = "RotorGII"
+const RotorGIII = "RotorGIII"
 
 const rotorI = "EKMFLGDQVZNTOWYHXUSPAIBRCJ"
 const rotorII = "AJDKSIRUXBLHWTMCQGZNPYFVOE"
@@ -27,43 +45,106 @@ const rotorVIII = "FKQHTLXOCBJSPDZRAMEWNIUYGV"
 const rotorBeta = "LEYJVCNIXWPBQMDRTAKZGFUHOS"
 const rotorGamma = "FSOKANUERHMBTIYCWLQPZXVGJD"
 
+// Commercial Enigma D, and the Swiss K
+const rotorDI = "LPGSZMHAEOQKVXRFYBUTNICJDW"
+const rotorDII = "SLVGBTFXJQOHEWIRZYAMKPCNDU"
+const rotorDIII = "CJGDPSHKTURAWZXFMYNQOBVLIE"
+const rotorKI = "PEZUOHXSCVFMTBGLRINQJWAYDK"
+const rotorKII = "ZOUESYDKFWPCIQXHMVBLGNJRAT"
+const rotorKIII = "EHRVXGAOBQUSIMZFLYNWKTPDJC"
+
+// Enigma T, Tirpitz
+const rotorTI = "KPTYUELOCVGRFQDANJMBSWHZXI"
+const rotorTII = "UPHZLWEQMTDJXCAKSOIGVBYFNR"
+const rotorTIII = "QUDLYRFEKONVZAXWHMGPJBSICT"
+const rotorTIV = "CIWTBKXNRESPFLYDAGVHQUOJZM"
+const rotorTV = "UAXGISNJBVERDYLFZWTPCKOHMQ"
+const rotorTVI = "XFUZGALVHCNYSEWQTDMRBKPIOJ"
+const rotorTVII = "BJVFTXPLNAYOZIKWGDQERUCHSM"
+const rotorTVIII = "YMTPNZHWKODAJXELUQVGCBISFR"
+
+// Abwehr Enigma G, as wired in machine G-312
+const rotorGI = "DMTWSILRUYQNKFEJCAZBPGXOHV"
+const rotorGII = "HQZGPJTMOBLNCIFDYAWVEUSRKX"
+const rotorGIII = "UQNTLSZFMREHDPXKIBVYGJCWOA"
+
+var rotorWirings = map[string]string{
+	RotorI:     rotorI,
+	RotorII:    rotorII,
+	RotorIII:   rotorIII,
+	RotorIV:    rotorIV,
+	RotorV:     rotorV,
+	RotorVI:    rotorVI,
+	RotorVII:   rotorVII,
+	RotorVIII:  rotorVIII,
+	RotorBeta:  rotorBeta,
+	RotorGamma: rotorGamma,
+	RotorDI:    rotorDI,
+	RotorDII:   rotorDII,
+	RotorDIII:  rotorDIII,
+	RotorKI:    rotorKI,
+	RotorKII:   rotorKII,
+	RotorKIII:  rotorKIII,
+	RotorTI:    rotorTI,
+	RotorTII:   rotorTII,
+	RotorTIII:  rotorTIII,
+	RotorTIV:   rotorTIV,
+	RotorTV:    rotorTV,
+	RotorTVI:   rotorTVI,
+	RotorTVII:  rotorTVII,
+	RotorTVIII: rotorTVIII,
+	RotorGI:    rotorGI,
+	RotorGII:   rotorGII,
+	RotorGIII:  rotorGIII,
+}
+
 func getRotor(k string) (string, error) {
-	m := map[string]string{
-		RotorI:     rotorI,
-		RotorII:    rotorII,
-		RotorIII:   rotorIII,
-		RotorIV:    rotorIV,
-		RotorV:     rotorV,
-		RotorVI:    rotorVI,
-		RotorVII:   rotorVII,
-		RotorVIII:  rotorVIII,
-		RotorBeta:  rotorBeta,
-		RotorGamma: rotorGamma,
-	}
-	r, ok := m[k]
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := rotorWirings[k]
 	if !ok {
-		return "", fmt.Errorf("unknown rotor: %s", k)
+		return "", &UnknownComponentError{Kind: "rotor", Name: k}
 	}
 	return r, nil
 }
 
 // Notches
+var rotorNotches = map[string][]int{
+	RotorI:     {16},
+	RotorII:    {4},
+	RotorIII:   {21},
+	RotorIV:    {9},
+	RotorV:     {25},
+	RotorVI:    {12, 25},
+	RotorVII:   {12, 25},
+	RotorVIII:  {12, 25},
+	RotorBeta:  nil,
+	RotorGamma: nil,
+	RotorDI:    {24},
+	RotorDII:   {4},
+	RotorDIII:  {13},
+	RotorKI:    {24},
+	RotorKII:   {4},
+	RotorKIII:  {13},
+	RotorTI:    {4, 10, 16, 22, 25},
+	RotorTII:   {5, 11, 17, 22, 25},
+	RotorTIII:  {4, 10, 16, 22, 25},
+	RotorTIV:   {5, 11, 17, 22, 25},
+	RotorTV:    {2, 5, 10, 17, 24},
+	RotorTVI:   {4, 8, 12, 16, 23},
+	RotorTVII:  {2, 5, 10, 17, 24},
+	RotorTVIII: {4, 8, 12, 16, 23},
+	RotorGI:    {0, 1, 2, 4, 5, 6, 8, 10, 11, 14, 15, 16, 18, 20, 21, 22, 25},
+	RotorGII:   {0, 2, 3, 5, 6, 7, 10, 12, 13, 16, 18, 19, 21, 24, 25},
+	RotorGIII:  {0, 4, 5, 7, 10, 12, 13, 17, 20, 22, 23},
+}
+
 func getNotches(k string) ([]int, error) {
-	m := map[string][]int{
-		RotorI:     {16},
-		RotorII:    {4},
-		RotorIII:   {21},
-		RotorIV:    {9},
-		RotorV:     {25},
-		RotorVI:    {12, 25},
-		RotorVII:   {12, 25},
-		RotorVIII:  {12, 25},
-		RotorBeta:  nil,
-		RotorGamma: nil,
-	}
-	n, ok := m[k]
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	n, ok := rotorNotches[k]
 	if !ok {
-		return nil, fmt.Errorf("unknown rotor: %s", k)
+		return nil, &UnknownComponentError{Kind: "rotor", Name: k}
 	}
 	return n, nil
 }
@@ -74,24 +155,60 @@ const ReflectorB = "ReflectorB"
 const ReflectorC = "ReflectorC"
 const ReflectorBThin = "ReflectorBThin"
 const ReflectorCThin = "ReflectorCThin"
+const ReflectorCommercial = "ReflectorCommercial"
+const ReflectorT = "ReflectorT"
+const ReflectorG = "ReflectorG"
 
 const reflectorA = "EJMZALYXVBWFCRQUONTSPIKHGD"
 const reflectorB = "YRUHQSLDPXNGOKMIEBFZCWVJAT"
 const reflectorC = "FVPJIAOYEDRZXWGCTKUQSBNMHL"
 const reflectorBThin = "ENKQAUYWJICOPBLMDXZVFTHRGS"
 const reflectorCThin = "RDOBJNTKVEHMLFCWZAXGYIPSUQ"
+const reflectorCommercial = "IMETCGFRAYSQBZXWLHKDVUPOJN"
+const reflectorT = "GEKPBTAUMOCNILJDXZYFHWVQSR"
+const reflectorG = "RULQMZJSYGOCETKWDAHNBXPVIF"
+
+var reflectorWirings = map[string]string{
+	ReflectorA:          reflectorA,
+	ReflectorB:          reflectorB,
+	ReflectorC:          reflectorC,
+	ReflectorBThin:      reflectorBThin,
+	ReflectorCThin:      reflectorCThin,
+	ReflectorCommercial: reflectorCommercial,
+	ReflectorT:          reflectorT,
+	ReflectorG:          reflectorG,
+}
 
 func getReflector(k string) (string, error) {
-	m := map[string]string{
-		ReflectorA:     reflectorA,
-		ReflectorB:     reflectorB,
-		ReflectorC:     reflectorC,
-		ReflectorBThin: reflectorBThin,
-		ReflectorCThin: reflectorCThin,
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := reflectorWirings[k]
+	if !ok {
+		return "", &UnknownComponentError{Kind: "reflector", Name: k}
 	}
-	r, ok := m[k]
+	return r, nil
+}
+
+// Entry Wheels Names of the entry wheels (Eintrittswalze) connecting the keyboard to the rotors
+const EntryWheelABC = "EntryWheelABC"
+const EntryWheelQWERTZ = "EntryWheelQWERTZ"
+const EntryWheelT = "EntryWheelT"
+
+// entry wheel configurations list the keys wired to each contact, in order of the contacts
+const entryWheelABC = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+const entryWheelQWERTZ = "QWERTZUIOASDFGHJKPYXCVBNML"
+const entryWheelT = "KZROUQHYAIGBLWVSTDXFPNMCJE"
+
+var entryWheelWirings = map[string]string{
+	EntryWheelABC:    entryWheelABC,
+	EntryWheelQWERTZ: entryWheelQWERTZ,
+	EntryWheelT:      entryWheelT,
+}
+
+func getEntryWheel(k string) (string, error) {
+	r, ok := entryWheelWirings[k]
 	if !ok {
-		return "", fmt.Errorf("unknown reflector: %s", k)
+		return "", &UnknownComponentError{Kind: "entry wheel", Name: k}
 	}
 	return r, nil
 }
@@ -0,0 +1,153 @@
+package enigma
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the complete setup of a machine, as stored in JSON or YAML configuration files
+type Config struct {
+	// Model is the name of a historical model, against which the setup is validated, or empty for none
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+	// Rotors are ordered from left to right, the reverse of the order taken by New
+	Rotors    []*RotorConfiguration `json:"rotors" yaml:"rotors"`
+	Reflector *ReflectorConfig      `json:"reflector" yaml:"reflector"`
+	// EntryWheel and Stepper are those of the model if one is given, otherwise the defaults of New when empty
+	EntryWheel string `json:"entryWheel,omitempty" yaml:"entryWheel,omitempty"`
+	Stepper    string `json:"stepper,omitempty" yaml:"stepper,omitempty"`
+	Plugboard  string `json:"plugboard,omitempty" yaml:"plugboard,omitempty"`
+}
+
+// ReflectorConfig is the setup of the reflector, with the pairs in Bletchley notation for a rewired UKW-D
+type ReflectorConfig struct {
+	Name     string `json:"name" yaml:"name"`
+	Position int    `json:"position,omitempty" yaml:"position,omitempty"`
+	Wiring   string `json:"wiring,omitempty" yaml:"wiring,omitempty"`
+}
+
+// Steppers Names of the stepping mechanisms for use in configuration files
+const StepperLever = "Lever"
+const StepperOdometer = "Odometer"
+const StepperCog = "Cog"
+
+func getStepper(k string) (Stepper, error) {
+	m := map[string]Stepper{
+		StepperLever:    LeverStepper{},
+		StepperOdometer: OdometerStepper{},
+		StepperCog:      CogStepper{},
+	}
+	s, ok := m[k]
+	if !ok {
+		return nil, &UnknownComponentError{Kind: "stepper", Name: k}
+	}
+	return s, nil
+}
+
+func stepperName(s Stepper) string {
+	switch s.(type) {
+	case LeverStepper:
+		return StepperLever
+	case OdometerStepper:
+		return StepperOdometer
+	case CogStepper:
+		return StepperCog
+	}
+	return ""
+}
+
+// LoadConfig reads a JSON or YAML configuration file and instantiates the machine it describes
+func LoadConfig(r io.Reader) (*Enigma, error) {
+	c := &Config{}
+	err := yaml.NewDecoder(r).Decode(c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration: %w", err)
+	}
+	return c.Enigma()
+}
+
+// Enigma instantiates the machine described by the configuration
+func (c *Config) Enigma(opts ...Option) (*Enigma, error) {
+	if c == nil {
+		return nil, ErrMissingConfig
+	}
+	if c.Reflector == nil {
+		return nil, ErrNoReflector
+	}
+	confs := c.rightToLeft()
+	reflector := c.Reflector.Name
+	options := []Option{}
+	if c.Reflector.Wiring != "" {
+		if reflector != ReflectorD {
+			return nil, fmt.Errorf("%w: reflector %s cannot be rewired", ErrInvalidWiring, reflector)
+		}
+		ukwd, err := NewReflectorD(c.Reflector.Wiring, NotationBletchley)
+		if err != nil {
+			return nil, err
+		}
+		reflector = ""
+		options = append(options, WithReflector(ukwd))
+	}
+	if c.Reflector.Position != 0 {
+		options = append(options, WithReflectorPosition(c.Reflector.Position))
+	}
+	if c.EntryWheel != "" {
+		options = append(options, WithEntryWheel(c.EntryWheel))
+	}
+	if c.Stepper != "" {
+		s, err := getStepper(c.Stepper)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithStepper(s))
+	}
+	options = append(options, opts...)
+	if c.Model != "" {
+		return NewFromModel(c.Model, confs, reflector, c.Plugboard, options...)
+	}
+	return New(confs, reflector, c.Plugboard, options...)
+}
+
+// rightToLeft returns the rotors in the order taken by New
+func (c *Config) rightToLeft() []*RotorConfiguration {
+	confs := []*RotorConfiguration{}
+	for i := len(c.Rotors) - 1; i >= 0; i-- {
+		confs = append(confs, c.Rotors[i])
+	}
+	return confs
+}
+
+// Config returns the complete setup of the machine with the rotors in their current positions, from which it can be
+// instantiated again. A custom stepping mechanism is not recorded
+func (e *Enigma) Config() *Config {
+	c := &Config{
+		Model: e.model,
+		Reflector: &ReflectorConfig{
+			Name:     e.reflector.Name,
+			Position: e.reflector.position,
+		},
+		EntryWheel: e.entry.Name,
+		Stepper:    stepperName(e.stepper),
+		Plugboard:  e.plugs.String(),
+	}
+	for i := len(e.rotors) - 1; i >= 0; i-- {
+		c.Rotors = append(c.Rotors, e.rotors[i].State())
+	}
+	if e.reflector.Name == ReflectorD {
+		c.Reflector.Wiring = reflectorPairs(e.reflector)
+	}
+	return c
+}
+
+// reflectorPairs lists the pairs wired by a rewirable reflector in Bletchley notation, leaving out the fixed J-Y pair
+func reflectorPairs(r *Rotor) string {
+	pairs := []string{}
+	for i, j := range r.connections[0] {
+		if j > i && i != int('J'-runeOffset) {
+			pairs = append(pairs, string([]rune{rune(i) + runeOffset, rune(j) + runeOffset}))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,172 @@
+package enigma
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "yaml",
+			input: `
+model: M3
+rotors:
+  - name: RotorII
+    position: 1
+    ringSetting: 1
+  - name: RotorIV
+    position: 11
+    ringSetting: 20
+  - name: RotorV
+    position: 0
+    ringSetting: 11
+reflector:
+  name: ReflectorB
+plugboard: AV BS CG DL FU HZ IN KM OW RX
+`,
+			expected: "AUFKLXABTEILUNG",
+		}, {
+			name: "json",
+			input: `{
+	"model": "M3",
+	"rotors": [
+		{"name": "RotorII", "position": 1, "ringSetting": 1},
+		{"name": "RotorIV", "position": 11, "ringSetting": 20},
+		{"name": "RotorV", "position": 0, "ringSetting": 11}
+	],
+	"reflector": {"name": "ReflectorB"},
+	"plugboard": "AV BS CG DL FU HZ IN KM OW RX"
+}`,
+			expected: "AUFKLXABTEILUNG",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := LoadConfig(strings.NewReader(tt.input))
+			assert.Nil(t, err)
+			res, err := e.Encode("EDPUDNRGYSZRCXN")
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res, "decrypted message should match")
+		})
+	}
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	ukwd, err := NewReflectorD("AB CD EF GH IK LM NO PQ RS TU VW XZ", NotationGerman)
+	assert.Nil(t, err)
+	tests := []struct {
+		name      string
+		model     string
+		rotors    []*RotorConfiguration
+		reflector string
+		plugs     string
+		opts      []Option
+	}{
+		{
+			name:  "model",
+			model: ModelG,
+			rotors: []*RotorConfiguration{
+				{Name: RotorGIII, Position: 25, RingSetting: 5},
+				{Name: RotorGII, Position: 24},
+				{Name: RotorGI, Position: 1, RingSetting: 3},
+			},
+			reflector: ReflectorG,
+			opts:      []Option{WithReflectorPosition(4)},
+		}, {
+			name: "rewired reflector",
+			rotors: []*RotorConfiguration{
+				{Name: RotorIII, Position: 5, RingSetting: 6},
+				{Name: RotorII, Position: 23, RingSetting: 1},
+				{Name: RotorI},
+			},
+			plugs: "AZ BC XT",
+			opts:  []Option{WithReflector(ukwd), WithStepper(OdometerStepper{})},
+		}, {
+			name: "custom wiring",
+			rotors: []*RotorConfiguration{
+				{Name: "Shift", Configuration: "BCDEFGHIJKLMNOPQRSTUVWXYZA", Notches: []int{3, 7}, RingSetting: 2},
+				{Name: RotorII},
+				{Name: RotorI},
+			},
+			reflector: ReflectorC,
+			opts:      []Option{WithEntryWheel(EntryWheelQWERTZ)},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			var e *Enigma
+			var err error
+			if tt.model != "" {
+				e, err = NewFromModel(tt.model, tt.rotors, tt.reflector, tt.plugs, tt.opts...)
+			} else {
+				e, err = New(tt.rotors, tt.reflector, tt.plugs, tt.opts...)
+			}
+			assert.Nil(t, err)
+
+			j, err := json.Marshal(e.Config())
+			assert.Nil(t, err)
+			fromJSON, err := LoadConfig(bytes.NewReader(j))
+			assert.Nil(t, err)
+			y, err := yaml.Marshal(e.Config())
+			assert.Nil(t, err)
+			fromYAML, err := LoadConfig(bytes.NewReader(y))
+			assert.Nil(t, err)
+			assert.Equal(t, e.Config(), fromJSON.Config(), "configuration should match")
+			assert.Equal(t, e.Config(), fromYAML.Config(), "configuration should match")
+
+			input := strings.Repeat("ENIGMA", 20)
+			expected, err := e.Encode(input)
+			assert.Nil(t, err)
+			res, err := fromJSON.Encode(input)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, res, "encoded string should match")
+			res, err = fromYAML.Encode(input)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, res, "encoded string should match")
+		})
+	}
+}
+
+func TestInvalidLoadConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "malformed",
+			input: `{"rotors": [`,
+		}, {
+			name:  "no reflector",
+			input: `{"rotors": [{"name": "RotorI"}, {"name": "RotorII"}, {"name": "RotorIII"}]}`,
+		}, {
+			name:  "unknown stepper",
+			input: `{"rotors": [{"name": "RotorI"}, {"name": "RotorII"}, {"name": "RotorIII"}], "reflector": {"name": "ReflectorB"}, "stepper": "Gear"}`,
+		}, {
+			name:  "rewired fixed reflector",
+			input: `{"rotors": [{"name": "RotorI"}, {"name": "RotorII"}, {"name": "RotorIII"}], "reflector": {"name": "ReflectorB", "wiring": "AB"}}`,
+		}, {
+			name:  "invalid for model",
+			input: `{"model": "M3", "rotors": [{"name": "RotorI"}, {"name": "RotorII"}, {"name": "RotorIII"}], "reflector": {"name": "ReflectorA"}}`,
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := LoadConfig(strings.NewReader(tt.input))
+			assert.Nil(t, e)
+			assert.Error(t, err)
+		})
+	}
+}
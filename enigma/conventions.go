@@ -0,0 +1,271 @@
+package enigma
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Conventions Names of the plaintext conventions of the operators, selectable per machine with WithConventions.
+// Both write X between words and for full stops, Y for commas, UD for question marks, XX for colons, YY for dashes,
+// KK around brackets and AE, OE, UE and SS for umlauts and eszett. The punctuation marks and numbers are written as
+// words of their own. The army spelled out numbers digit by digit, the navy wrote them between Y with the letters of
+// the top row of the keyboard, Q for 1 to P for 0. Abbreviations are written with each letter doubled, OOKKWW for OKW
+const ConventionsArmy = "Army"
+const ConventionsNavy = "Navy"
+
+var armyDigits = []string{"NULL", "EINS", "ZWO", "DREI", "VIER", "FUENF", "SECHS", "SIEBEN", "ACHT", "NEUN"}
+
+// navyDigits are the top row of the keyboard, in the order of the digits 1 to 9 and then 0
+const navyDigits = "QWERTZUIOP"
+
+var punctuation = map[rune]string{
+	',': "Y",
+	'?': "UD",
+	':': "XX",
+	'-': "YY",
+	'(': "KK",
+	')': "KK",
+}
+
+var umlauts = map[rune]string{
+	'Ä': "AE",
+	'Ö': "OE",
+	'Ü': "UE",
+	'ß': "SS",
+	'ẞ': "SS",
+}
+
+// renderPunctuation reads the words standing for punctuation marks, other than the brackets
+var renderPunctuation = map[string]string{
+	"Y":  ",",
+	"UD": "?",
+	"XX": ":",
+	"YY": " -",
+}
+
+var navyNumber = regexp.MustCompile("^Y([" + navyDigits + "]+)Y$")
+
+// WithConventions has the machine apply the plaintext conventions of the operators, so that Encode prepares the
+// text with Prepare and Decode renders the deciphered text with Render. All ciphertext is then [A-Z] only
+func WithConventions(conventions string) Option {
+	return func(e *Enigma) error {
+		if conventions != ConventionsArmy && conventions != ConventionsNavy {
+			return &UnknownComponentError{Kind: "conventions", Name: conventions}
+		}
+		e.conventions = conventions
+		return nil
+	}
+}
+
+// Prepare writes a plaintext as an operator would with the given conventions, leaving only the letters [A-Z].
+// Characters with no convention are an error, at an offset counted in runes of s. Words of two or more letters
+// written wholly in capitals, such as OKW, are taken as abbreviations, unless the whole text is in capitals
+func Prepare(s string, conventions string) (string, error) {
+	if conventions != ConventionsArmy && conventions != ConventionsNavy {
+		return "", &UnknownComponentError{Kind: "conventions", Name: conventions}
+	}
+	original := []rune(s)
+	text := make([]rune, len(original))
+	for i, c := range original {
+		text[i] = unicode.ToUpper(c)
+	}
+	abbreviations := strings.IndexFunc(s, unicode.IsLower) >= 0
+	words := []string{}
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	// stop records that the text ends with a full stop, written as a final X
+	stop := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if !unicode.IsSpace(c) {
+			stop = false
+		}
+		switch {
+		case abbreviations && isAbbreviation(original, i):
+			for ; i < len(text) && isAllowedCharacter(text[i]); i++ {
+				word.WriteString(string([]rune{text[i], text[i]}))
+			}
+			i--
+		case isAllowedCharacter(c):
+			word.WriteRune(c)
+		case unicode.IsSpace(c):
+			flush()
+		case c == '.':
+			flush()
+			stop = true
+		case '0' <= c && c <= '9':
+			j := i
+			for j < len(text) && '0' <= text[j] && text[j] <= '9' {
+				j++
+			}
+			flush()
+			words = append(words, spellNumber(text[i:j], conventions))
+			i = j - 1
+		case punctuation[c] != "":
+			flush()
+			words = append(words, punctuation[c])
+		case umlauts[c] != "":
+			word.WriteString(umlauts[c])
+		default:
+			return "", &UnencodableCharacterError{Rune: c, Offset: i}
+		}
+	}
+	flush()
+	res := strings.Join(words, "X")
+	if stop {
+		res += "X"
+	}
+	return res, nil
+}
+
+// isAbbreviation reports whether an abbreviation, a word of at least two letters [A-Z] written wholly in capitals,
+// starts at position i of the text
+func isAbbreviation(text []rune, i int) bool {
+	if i > 0 && unicode.IsLetter(text[i-1]) {
+		return false
+	}
+	j := i
+	for j < len(text) && 'A' <= text[j] && text[j] <= 'Z' {
+		j++
+	}
+	return j-i >= 2 && (j == len(text) || !unicode.IsLetter(text[j]))
+}
+
+// spellNumber writes the digits of a number with the given conventions
+func spellNumber(digits []rune, conventions string) string {
+	var b strings.Builder
+	if conventions == ConventionsNavy {
+		b.WriteString("Y")
+	}
+	for _, d := range digits {
+		n := int(d - '0')
+		if conventions == ConventionsNavy {
+			b.WriteByte(navyDigits[(n+9)%10])
+			continue
+		}
+		b.WriteString(armyDigits[n])
+	}
+	if conventions == ConventionsNavy {
+		b.WriteString("Y")
+	}
+	return b.String()
+}
+
+// Render turns deciphered text written with the given conventions back into readable German, as best it can. The
+// text is split into words at each X, and punctuation, numbers and abbreviations are only recognised as whole words.
+// An X within a word is taken for a space, so the result may need a human eye
+func Render(s string, conventions string) string {
+	var b strings.Builder
+	// attached records that the next word follows an opening bracket or the start, needing no space
+	attached := true
+	open := false
+	for _, w := range splitWords(s) {
+		switch {
+		case w == "":
+		case w == "KK" && !open:
+			if !attached {
+				b.WriteString(" ")
+			}
+			b.WriteString("(")
+			attached = true
+			open = true
+		case w == "KK":
+			b.WriteString(")")
+			attached = false
+			open = false
+		case renderPunctuation[w] != "":
+			b.WriteString(renderPunctuation[w])
+			attached = false
+		default:
+			if !attached {
+				b.WriteString(" ")
+			}
+			b.WriteString(readWord(w, conventions))
+			attached = false
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// splitWords splits prepared text into its words at each X, taking XX standing between separators as the colon
+func splitWords(s string) []string {
+	words := []string{}
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "XX") && (i+2 == len(s) || s[i+2] == 'X') {
+			words = append(words, "XX")
+			i += 2
+			continue
+		}
+		j := strings.IndexByte(s[i:], 'X')
+		if j < 0 {
+			return append(words, s[i:])
+		}
+		words = append(words, s[i:i+j])
+		i += j
+	}
+	return words
+}
+
+// readWord reads a word of deciphered text as an abbreviation, or as a number written with the given conventions
+func readWord(w string, conventions string) string {
+	if a, ok := readAbbreviation(w); ok {
+		return a
+	}
+	if conventions == ConventionsNavy {
+		if m := navyNumber.FindStringSubmatch(w); m != nil {
+			digits := []rune{}
+			for _, c := range m[1] {
+				digits = append(digits, rune('0'+(strings.IndexRune(navyDigits, c)+1)%10))
+			}
+			return string(digits)
+		}
+		return w
+	}
+	return readNumber(w)
+}
+
+// readAbbreviation collapses a word of at least two letters written with each letter doubled
+func readAbbreviation(w string) (string, bool) {
+	if len(w) < 4 || len(w)%2 != 0 {
+		return "", false
+	}
+	letters := []byte{}
+	for i := 0; i < len(w); i += 2 {
+		if w[i] != w[i+1] || !isAllowedCharacter(rune(w[i])) {
+			return "", false
+		}
+		letters = append(letters, w[i])
+	}
+	return string(letters), true
+}
+
+// readNumber converts a word spelled wholly of digits in the army convention back to the digits. A single digit is
+// left as the word, as EINS or ACHT, since it cannot be told from the German
+func readNumber(w string) string {
+	digits := []rune{}
+	rest := w
+	for rest != "" {
+		found := false
+		for n, d := range armyDigits {
+			if strings.HasPrefix(rest, d) {
+				digits = append(digits, rune('0'+n))
+				rest = rest[len(d):]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return w
+		}
+	}
+	if len(digits) < 2 {
+		return w
+	}
+	return string(digits)
+}
@@ -0,0 +1,186 @@
+package enigma
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepare(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		conventions string
+		expected    string
+	}{
+		{
+			name:        "army",
+			input:       "Angriff um 1830 Uhr. Gegner stark, Verluste?",
+			conventions: ConventionsArmy,
+			expected:    "ANGRIFFXUMXEINSACHTDREINULLXUHRXGEGNERXSTARKXYXVERLUSTEXUD",
+		}, {
+			name:        "navy",
+			input:       "Standort 0830, Kurs 270",
+			conventions: ConventionsNavy,
+			expected:    "STANDORTXYPIEPYXYXKURSXYWUPY",
+		}, {
+			name:        "umlauts and punctuation",
+			input:       "Fünf Flöße (Küste): Straße - Ende.",
+			conventions: ConventionsArmy,
+			expected:    "FUENFXFLOESSEXKKXKUESTEXKKXXXXSTRASSEXYYXENDEX",
+		}, {
+			name:        "abbreviation",
+			input:       "Meldung an OKW, (OKH).",
+			conventions: ConventionsArmy,
+			expected:    "MELDUNGXANXOOKKWWXYXKKXOOKKHHXKKX",
+		}, {
+			name:        "all capitals",
+			input:       "MELDUNG AN OKW",
+			conventions: ConventionsArmy,
+			expected:    "MELDUNGXANXOKW",
+		}, {
+			name:        "surrounding spaces",
+			input:       "  Ende.  ",
+			conventions: ConventionsArmy,
+			expected:    "ENDEX",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res, err := Prepare(tt.input, tt.conventions)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res, "prepared text should match")
+		})
+	}
+}
+
+func TestInvalidPrepare(t *testing.T) {
+	_, err := Prepare("Ende!", ConventionsArmy)
+	var char *UnencodableCharacterError
+	assert.True(t, errors.As(err, &char))
+	assert.Equal(t, &UnencodableCharacterError{Rune: '!', Offset: 4}, char)
+
+	_, err = Prepare("   Ende!", ConventionsArmy)
+	assert.True(t, errors.As(err, &char))
+	assert.Equal(t, &UnencodableCharacterError{Rune: '!', Offset: 7}, char, "offset should count the leading spaces")
+
+	_, err = Prepare("Ende", "Luftwaffe")
+	assert.True(t, errors.Is(err, ErrUnknownComponent))
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		conventions string
+		expected    string
+	}{
+		{
+			name:        "army",
+			input:       "ANGRIFFXUMXEINSACHTDREINULLXUHRXGEGNERXSTARKXYXVERLUSTEXUD",
+			conventions: ConventionsArmy,
+			expected:    "ANGRIFF UM 1830 UHR GEGNER STARK, VERLUSTE?",
+		}, {
+			name:        "navy",
+			input:       "STANDORTXYPIEPYXYXKURSXYWUPY",
+			conventions: ConventionsNavy,
+			expected:    "STANDORT 0830, KURS 270",
+		}, {
+			name:        "brackets and colon",
+			input:       "FUENFXFLOESSEXKKXKUESTEXKKXXXXSTRASSEXYYXENDEX",
+			conventions: ConventionsArmy,
+			expected:    "FUENF FLOESSE (KUESTE): STRASSE - ENDE",
+		}, {
+			name:        "single digit word",
+			input:       "EINSATZXDREI",
+			conventions: ConventionsArmy,
+			expected:    "EINSATZ DREI",
+		}, {
+			name:        "codes within words",
+			input:       "FREUDEXINXBAYERNXYXGEBAEUDEXUDX",
+			conventions: ConventionsArmy,
+			expected:    "FREUDE IN BAYERN, GEBAEUDE?",
+		}, {
+			name:        "abbreviation",
+			input:       "MELDUNGXANXOOKKWWXYXKKXOOKKHHXKKX",
+			conventions: ConventionsArmy,
+			expected:    "MELDUNG AN OKW, (OKH)",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, Render(tt.input, tt.conventions), "rendered text should match")
+		})
+	}
+}
+
+func TestPrepareRender(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		conventions string
+		expected    string
+	}{
+		{
+			name:        "question mark within words",
+			input:       "Freude in Bayern. Gebäude?",
+			conventions: ConventionsArmy,
+			expected:    "FREUDE IN BAYERN GEBAEUDE?",
+		}, {
+			name:        "names",
+			input:       "Ludwig und Rudolf nach Budapest, dann Yalta.",
+			conventions: ConventionsArmy,
+			expected:    "LUDWIG UND RUDOLF NACH BUDAPEST, DANN YALTA",
+		}, {
+			name:        "digit words",
+			input:       "Acht Mann verletzt, fünf tot.",
+			conventions: ConventionsArmy,
+			expected:    "ACHT MANN VERLETZT, FUENF TOT",
+		}, {
+			name:        "numbers",
+			input:       "Angriff um 1830 Uhr: Abschnitt (Ost) - Yser.",
+			conventions: ConventionsArmy,
+			expected:    "ANGRIFF UM 1830 UHR: ABSCHNITT (OST) - YSER",
+		}, {
+			name:        "navy",
+			input:       "Kurs 270, Fahrt 12. Treffen U 264?",
+			conventions: ConventionsNavy,
+			expected:    "KURS 270, FAHRT 12 TREFFEN U 264?",
+		}, {
+			name:        "abbreviations",
+			input:       "Meldung an OKW und OKH (BdU).",
+			conventions: ConventionsNavy,
+			expected:    "MELDUNG AN OKW UND OKH (BDU)",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			prepared, err := Prepare(tt.input, tt.conventions)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, Render(prepared, tt.conventions), "text should survive the conventions")
+		})
+	}
+}
+
+func TestWithConventions(t *testing.T) {
+	k, err := ParseKey("B II-IV-V 02-21-12 BLA AV BS CG DL FU HZ IN KM OW RX")
+	assert.Nil(t, err)
+	e, err := k.Enigma(WithConventions(ConventionsNavy))
+	assert.Nil(t, err)
+	cipher, err := e.EncryptMessage("BLA", "Kurs 270, Fahrt 12.")
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[A-Z]+$", cipher, "ciphertext should be letters only")
+	plain, err := e.DecryptMessage("BLA", Group(cipher, GroupSizeNavy))
+	assert.Nil(t, err)
+	assert.Equal(t, "KURS 270, FAHRT 12", plain, "rendered plaintext should match")
+
+	_, err = k.Enigma(WithConventions("Luftwaffe"))
+	assert.True(t, errors.Is(err, ErrUnknownComponent))
+}
@@ -8,20 +8,83 @@ import (
 
 // Enigma is the functioning enigma machine capable of encoding a message
 type Enigma struct {
-	plugs      *Plugboard
-	rotors     []*Rotor
-	rotorCount int
-	reflector  *Rotor
+	plugs       *Plugboard
+	rotors      []*Rotor
+	rotorCount  int
+	reflector   *Rotor
+	entry       *Rotor
+	stepper     Stepper
+	model       string
+	start       Snapshot
+	observer    Observer
+	logger      Logger
+	compiled    *permutationCache
+	conventions string
+}
+
+// Option applies an optional setting to an enigma machine during instantiation
+type Option func(*Enigma) error
+
+// WithStepper replaces the default lever stepping mechanism of the M3 with the given stepper
+func WithStepper(s Stepper) Option {
+	return func(e *Enigma) error {
+		if s == nil {
+			return fmt.Errorf("stepper must not be nil")
+		}
+		e.stepper = s
+		return nil
+	}
+}
+
+// WithEntryWheel replaces the default alphabetical entry wheel of the military machines, such as with the QWERTZ
+// ordered entry wheel of the commercial machines
+func WithEntryWheel(name string) Option {
+	return func(e *Enigma) error {
+		etw, err := GetEntryWheel(name)
+		if err != nil {
+			return err
+		}
+		e.entry = etw
+		return nil
+	}
+}
+
+// WithReflector replaces the named reflector with one built by the caller, such as a rewired UKW-D. The reflector
+// name passed to New may then be left empty. The machine takes a copy, so one reflector may be given to many
+func WithReflector(r *Rotor) Option {
+	return func(e *Enigma) error {
+		if r == nil {
+			return fmt.Errorf("reflector must not be nil")
+		}
+		reflector := *r
+		e.reflector = &reflector
+		return nil
+	}
+}
+
+// WithReflectorPosition sets the reflector to one of its 26 positions, as possible on machines with a settable reflector
+func WithReflectorPosition(position int) Option {
+	return func(e *Enigma) error {
+		if e.reflector == nil {
+			return ErrNoReflector
+		}
+		if err := checkPosition("reflector "+e.reflector.Name, "reflector position", position); err != nil {
+			return err
+		}
+		e.reflector.position = position
+		return nil
+	}
 }
 
 // New instantiates an enigma machine from a barebones configuration
-func New(rotorConfs []*RotorConfiguration, reflector string, plugs string) (*Enigma, error) {
+func New(rotorConfs []*RotorConfiguration, reflector string, plugs string, opts ...Option) (*Enigma, error) {
+	confs := []*RotorConfiguration{}
 	for _, r := range rotorConfs {
-		err := fillRotorConfiguration(r)
+		c, err := fillRotorConfiguration(r)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Println(r.configuration)
+		confs = append(confs, c)
 	}
 	pb, err := parseStringPlugboard(plugs)
 	if err != nil {
@@ -29,70 +92,152 @@ func New(rotorConfs []*RotorConfiguration, reflector string, plugs string) (*Eni
 	}
 	p, err := newPlugboard(pb)
 	if err != nil {
-		return nil, fmt.Errorf("unable to instantiate plugboard: %v", err)
+		return nil, fmt.Errorf("unable to instantiate plugboard: %w", err)
+	}
+	var ref *Rotor
+	if reflector != "" {
+		ref, err = GetReflector(reflector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	etw, err := GetEntryWheel(EntryWheelABC)
+	if err != nil {
+		return nil, err
 	}
-	ref, err := GetReflector(reflector)
 	rotorCount := len(rotorConfs)
 	if rotorCount < 3 {
-		return nil, fmt.Errorf("insufficient rotors specified: %d", rotorCount)
+		return nil, fmt.Errorf("%w: at least 3 required: %d", ErrRotorCount, rotorCount)
 	}
 	rotors := []*Rotor{}
-	for _, r := range rotorConfs {
+	for _, r := range confs {
 		rot, err := newRotor(r)
 		if err != nil {
-			return nil, fmt.Errorf("unable to instantiate rotor: %s - %s", r.name, r.configuration)
+			return nil, fmt.Errorf("unable to instantiate rotor %s: %w", r.Name, err)
 		}
 		rotors = append(rotors, rot)
 	}
-	return &Enigma{
+	e := &Enigma{
 		plugs:      p,
 		rotors:     rotors,
 		rotorCount: rotorCount - 1,
 		reflector:  ref,
-	}, nil
-}
-
-// cycle steps the rotors of the enigma as done by the M3; i.e. 4th or greater rotor is static and double stepping of the 2nd rotor occurs
-func (e *Enigma) cycle() {
-	cycle1, cycle2 := false, false
-	if e.rotors[0].isNotchEngaged() {
-		cycle1 = true
+		entry:      etw,
+		stepper:    LeverStepper{},
 	}
-	if e.rotors[1].isNotchEngaged() {
-		cycle1 = true
-		cycle2 = true
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, err
+		}
 	}
-	e.rotors[0].cycle()
-	if cycle1 {
-		e.rotors[1].cycle()
+	if e.reflector == nil {
+		return nil, ErrNoReflector
 	}
-	if cycle2 {
-		e.rotors[2].cycle()
+	e.start = e.Snapshot()
+	e.logConstruction()
+	return e, nil
+}
+
+// Clone returns an independent copy of the machine for use alongside the original, such as in another goroutine. The
+// wiring of the components is immutable and shared, while the positions of the rotors and reflector are copied
+func (e *Enigma) Clone() *Enigma {
+	c := *e
+	c.rotors = make([]*Rotor, len(e.rotors))
+	for i, r := range e.rotors {
+		rotor := *r
+		c.rotors[i] = &rotor
+	}
+	reflector := *e.reflector
+	c.reflector = &reflector
+	c.start.Positions = append([]int(nil), e.start.Positions...)
+	return &c
+}
+
+// cycle steps the rotors of the enigma using the configured stepping mechanism
+func (e *Enigma) cycle() {
+	if e.observer != nil {
+		e.observeCycle()
+		return
 	}
+	e.stepper.Step(e.rotors, e.reflector)
 }
 
+// encode enciphers a single letter on the fast path of permute, unless an observer or logger needs the events of
+// the reference path in encodeTrace
 func (e *Enigma) encode(r rune) rune {
+	if e.observer != nil || e.logger != nil {
+		return e.encodeTrace(r, nil)
+	}
+	e.stepper.Step(e.rotors, e.reflector)
+	return rune(e.permute(int(r-runeOffset))) + runeOffset
+}
+
+// encodeTrace is the reference path, encoding a single letter, recording each stage of the signal path in the trace if one is given
+func (e *Enigma) encodeTrace(r rune, t *Trace) rune {
+	if e.observer != nil {
+		e.observer.KeyPressed(r)
+	}
 	in := int(r - runeOffset)
 	out := e.plugs.traverse(in)
+	if e.logger != nil {
+		e.logger.Debug("plugboard traversed", "in", string(r), "out", string(rune(out)+runeOffset))
+	}
+	if t != nil {
+		t.Plugboard = rune(out) + runeOffset
+		t.Before = e.Snapshot()
+	}
 	e.cycle()
+	out = e.entry.traverse(out, false)
+	if t != nil {
+		t.After = e.Snapshot()
+		t.EntryWheel = rune(out) + runeOffset
+	}
 	for _, r := range e.rotors {
 		out = r.traverse(out, true)
+		if t != nil {
+			t.Forward = append(t.Forward, rune(out)+runeOffset)
+		}
 	}
 	out = e.reflector.traverse(out, true)
+	if t != nil {
+		t.Reflector = rune(out) + runeOffset
+	}
 	for i := e.rotorCount; i >= 0; i-- {
 		out = e.rotors[i].traverse(out, false)
+		if t != nil {
+			t.Backward = append(t.Backward, rune(out)+runeOffset)
+		}
+	}
+	out = e.entry.traverse(out, true)
+	if t != nil {
+		t.EntryWheelReturn = rune(out) + runeOffset
+	}
+	lamp := rune(e.plugs.traverse(out)) + runeOffset
+	if e.logger != nil {
+		e.logger.Debug("plugboard traversed", "in", string(rune(out)+runeOffset), "out", string(lamp))
+		e.logger.Debug("key encoded", "in", string(r), "out", string(lamp), "positions", windows(e.Snapshot().Positions))
 	}
-	return rune(e.plugs.traverse(out)) + runeOffset
+	if e.observer != nil {
+		e.observer.LampLit(lamp)
+	}
+	return lamp
 }
 
 // Encode is the principal method of the package, making use of the enigma machine to encode a string an cycle the machine
 func (e *Enigma) Encode(s string) (string, error) {
+	if e.conventions != "" {
+		var err error
+		s, err = Prepare(s, e.conventions)
+		if err != nil {
+			return "", err
+		}
+	}
 	crib := []rune(strings.ToUpper(s))
 	cipher := []rune{}
-	for _, c := range crib {
+	for i, c := range crib {
 		if !isAllowedCharacter(c) {
 			if !isRetainCharacter(c) {
-				return string(cipher), fmt.Errorf("unencodeable character: %v", c)
+				return string(cipher), &UnencodableCharacterError{Rune: c, Offset: i}
 			}
 			cipher = append(cipher, c)
 			continue
@@ -102,6 +247,56 @@ func (e *Enigma) Encode(s string) (string, error) {
 	return string(cipher), nil
 }
 
+// Decode deciphers a string and cycles the machine. The enigma is reciprocal, so decoding is encoding from the same
+// start positions. With conventions the grouping is stripped first and the plaintext is rendered with Render
+func (e *Enigma) Decode(s string) (string, error) {
+	if e.conventions == "" {
+		return e.Encode(s)
+	}
+	res, err := e.Encode(Ungroup(s))
+	if err != nil {
+		return res, err
+	}
+	return Render(res, e.conventions), nil
+}
+
+// SetPositions turns the rotors to the given start positions, from left to right as seen in the windows, either
+// as letters "KDO" or as dash separated letters or numbers "11-04-15"
+func (e *Enigma) SetPositions(positions string) error {
+	p, err := parseKeySettings(positions, len(e.rotors))
+	if err != nil {
+		return fmt.Errorf("invalid positions: %w", err)
+	}
+	for i, r := range e.rotors {
+		r.position = p[len(p)-1-i]
+	}
+	return nil
+}
+
+// EncryptMessage encodes a message with the rotors turned to the given start positions. The machine is returned to
+// its previous positions afterwards, ready for the next message
+func (e *Enigma) EncryptMessage(startPositions, text string) (string, error) {
+	return e.atPositions(startPositions, e.Encode, text)
+}
+
+// DecryptMessage decodes a message with the rotors turned to the given start positions. The machine is returned to
+// its previous positions afterwards, ready for the next message
+func (e *Enigma) DecryptMessage(startPositions, text string) (string, error) {
+	return e.atPositions(startPositions, e.Decode, text)
+}
+
+// atPositions applies f to the text with the rotors turned to the given start positions, restoring the previous
+// positions afterwards
+func (e *Enigma) atPositions(startPositions string, f func(string) (string, error), text string) (string, error) {
+	s := e.Snapshot()
+	defer e.restore(s)
+	err := e.SetPositions(startPositions)
+	if err != nil {
+		return "", err
+	}
+	return f(text)
+}
+
 func isRetainCharacter(r rune) bool {
 	return ('0' <= r && r <= '9') || unicode.IsSpace(r)
 }
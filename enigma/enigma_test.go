@@ -19,20 +19,20 @@ func TestEncodeString(t *testing.T) {
 			name: "base",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{21},
 				},
 			},
 			reflector: ReflectorB,
@@ -43,20 +43,20 @@ func TestEncodeString(t *testing.T) {
 			name: "space",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{21},
 				},
 			},
 			reflector: ReflectorB,
@@ -67,20 +67,20 @@ func TestEncodeString(t *testing.T) {
 			name: "number",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{21},
 				},
 			},
 			reflector: ReflectorB,
@@ -91,20 +91,20 @@ func TestEncodeString(t *testing.T) {
 			name: "stepped",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
 				},
 			},
 			reflector: ReflectorB,
@@ -115,20 +115,20 @@ func TestEncodeString(t *testing.T) {
 			name: "double stepped",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      20,
-					notches:       []int{21},
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      20,
+					Notches:       []int{21},
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      3,
-					notches:       []int{4},
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      3,
+					Notches:       []int{4},
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
 				},
 			},
 			reflector: ReflectorB,
@@ -139,20 +139,20 @@ func TestEncodeString(t *testing.T) {
 			name: "double notched",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorVIII,
-					configuration: rotorVIII,
-					position:      11,
-					notches:       []int{12, 25},
+					Name:          RotorVIII,
+					Configuration: rotorVIII,
+					Position:      11,
+					Notches:       []int{12, 25},
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      3,
-					notches:       []int{4},
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      3,
+					Notches:       []int{4},
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
 				},
 			},
 			reflector: ReflectorB,
@@ -163,23 +163,23 @@ func TestEncodeString(t *testing.T) {
 			name: "ring setting",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
-					ringSetting:   1,
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
+					RingSetting:   1,
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
-					ringSetting:   0,
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
+					RingSetting:   0,
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
-					ringSetting:   0,
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
+					RingSetting:   0,
 				},
 			},
 			reflector: ReflectorB,
@@ -190,23 +190,23 @@ func TestEncodeString(t *testing.T) {
 			name: "plugboard",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
-					ringSetting:   0,
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
+					RingSetting:   0,
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
-					ringSetting:   0,
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
+					RingSetting:   0,
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
-					ringSetting:   0,
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
+					RingSetting:   0,
 				},
 			},
 			reflector: ReflectorB,
@@ -217,23 +217,23 @@ func TestEncodeString(t *testing.T) {
 			name: "everything",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorVIII,
-					configuration: rotorVIII,
-					position:      4,
-					notches:       []int{12, 25},
-					ringSetting:   0,
+					Name:          RotorVIII,
+					Configuration: rotorVIII,
+					Position:      4,
+					Notches:       []int{12, 25},
+					RingSetting:   0,
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      1,
-					notches:       []int{4},
-					ringSetting:   4,
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      1,
+					Notches:       []int{4},
+					RingSetting:   4,
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
-					ringSetting:   4,
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
+					RingSetting:   4,
 				},
 			},
 			reflector: ReflectorC,
@@ -244,29 +244,29 @@ func TestEncodeString(t *testing.T) {
 			name: "shark backwards compatible",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
-					ringSetting:   0,
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
+					RingSetting:   0,
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
-					ringSetting:   0,
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
+					RingSetting:   0,
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
-					ringSetting:   0,
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
+					RingSetting:   0,
 				}, {
-					name:          RotorBeta,
-					configuration: rotorBeta,
-					position:      0,
-					notches:       nil,
-					ringSetting:   0,
+					Name:          RotorBeta,
+					Configuration: rotorBeta,
+					Position:      0,
+					Notches:       nil,
+					RingSetting:   0,
 				},
 			},
 			reflector: ReflectorBThin,
@@ -277,29 +277,29 @@ func TestEncodeString(t *testing.T) {
 			name: "shark",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
-					ringSetting:   0,
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
+					RingSetting:   0,
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
-					ringSetting:   0,
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
+					RingSetting:   0,
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
-					ringSetting:   0,
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
+					RingSetting:   0,
 				}, {
-					name:          RotorGamma,
-					configuration: rotorGamma,
-					position:      1,
-					notches:       nil,
-					ringSetting:   0,
+					Name:          RotorGamma,
+					Configuration: rotorGamma,
+					Position:      1,
+					Notches:       nil,
+					RingSetting:   0,
 				},
 			},
 			reflector: ReflectorCThin,
@@ -333,20 +333,20 @@ func TestEncodeStringReverse(t *testing.T) {
 			name: "base",
 			rotors: []*RotorConfiguration{
 				{
-					name:          RotorIII,
-					configuration: rotorIII,
-					position:      0,
-					notches:       []int{21},
+					Name:          RotorIII,
+					Configuration: rotorIII,
+					Position:      0,
+					Notches:       []int{21},
 				}, {
-					name:          RotorII,
-					configuration: rotorII,
-					position:      0,
-					notches:       []int{4},
+					Name:          RotorII,
+					Configuration: rotorII,
+					Position:      0,
+					Notches:       []int{4},
 				}, {
-					name:          RotorI,
-					configuration: rotorI,
-					position:      0,
-					notches:       []int{16},
+					Name:          RotorI,
+					Configuration: rotorI,
+					Position:      0,
+					Notches:       []int{16},
 				},
 			},
 			reflector: ReflectorB,
@@ -363,7 +363,7 @@ func TestEncodeStringReverse(t *testing.T) {
 			res, err := e.Encode(tt.input)
 			assert.Nil(t, err)
 			for i, r := range tt.rotors {
-				e.rotors[i].position = r.position
+				e.rotors[i].position = r.Position
 			}
 			res, err = e.Encode(res)
 			assert.Nil(t, err)
@@ -419,7 +419,7 @@ func TestNew(t *testing.T) {
 			t.Parallel()
 			conf := []*RotorConfiguration{}
 			for _, r := range tt.rotors {
-				conf = append(conf, &RotorConfiguration{name: r.name, position: r.position, ringSetting: r.setting})
+				conf = append(conf, &RotorConfiguration{Name: r.name, Position: r.position, RingSetting: r.setting})
 			}
 			e, err := New(conf, tt.reflector, tt.plugboard)
 			assert.Nil(t, err)
@@ -430,3 +430,137 @@ func TestNew(t *testing.T) {
 	}
 
 }
+
+func TestEntryWheel(t *testing.T) {
+	tests := []struct {
+		name       string
+		entryWheel string
+		expected   string
+	}{
+		{
+			name:       "alphabetical",
+			entryWheel: EntryWheelABC,
+			expected:   "BDZGO",
+		}, {
+			name:       "qwertz",
+			entryWheel: EntryWheelQWERTZ,
+			expected:   "YWRLJ",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			conf := []*RotorConfiguration{
+				{Name: RotorIII},
+				{Name: RotorII},
+				{Name: RotorI},
+			}
+			e, err := New(conf, ReflectorB, "", WithEntryWheel(tt.entryWheel))
+			assert.Nil(t, err)
+			res, err := e.Encode("AAAAA")
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res, "encoded string should match")
+		})
+	}
+}
+
+func TestNewHonoursConfiguration(t *testing.T) {
+	custom := &RotorConfiguration{
+		Name:          "Shift",
+		Configuration: "BCDEFGHIJKLMNOPQRSTUVWXYZA",
+		Notches:       []int{0},
+	}
+	named := &RotorConfiguration{Name: RotorII}
+	e, err := New([]*RotorConfiguration{custom, named, {Name: RotorI}}, ReflectorB, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "BCDEFGHIJKLMNOPQRSTUVWXYZA", custom.Configuration, "configuration should not be refilled")
+	assert.Equal(t, []int{0}, custom.Notches, "notches should not be refilled")
+	assert.Equal(t, &RotorConfiguration{Name: RotorII}, named, "configuration should not be modified")
+	assert.Equal(t, 1, e.rotors[0].traverse(0, true), "custom wiring should be used")
+	assert.True(t, e.rotors[0].notches[0], "custom notches should be used")
+}
+
+func TestClone(t *testing.T) {
+	conf := []*RotorConfiguration{
+		{Name: RotorGIII, Position: 25, RingSetting: 5},
+		{Name: RotorGII, Position: 24},
+		{Name: RotorGI, Position: 1, RingSetting: 3},
+	}
+	e, err := NewFromModel(ModelG, conf, ReflectorG, "", WithReflectorPosition(4))
+	assert.Nil(t, err)
+	c := e.Clone()
+	for i := range e.rotors {
+		assert.NotSame(t, e.rotors[i], c.rotors[i], "rotors should be copied")
+		assert.Same(t, e.rotors[i].connections, c.rotors[i].connections, "rotor wiring should be shared")
+	}
+	assert.NotSame(t, e.reflector, c.reflector, "reflector should be copied")
+	assert.Same(t, e.plugs, c.plugs, "plugboard should be shared")
+
+	res, err := c.Encode("FVXOZIZXTXQCDEKCLJWWRMH")
+	assert.Nil(t, err)
+	assert.Equal(t, "ABWEHRNACHRICHTENDIENST", res, "decrypted message should match")
+	assert.Equal(t, Snapshot{Positions: []int{25, 24, 1}, Reflector: 4}, e.Snapshot(), "original should not move")
+
+	results := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		go func(m *Enigma) {
+			res, _ := m.Encode("FVXOZIZXTXQCDEKCLJWWRMH")
+			results <- res
+		}(e.Clone())
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "ABWEHRNACHRICHTENDIENST", <-results, "decrypted message should match")
+	}
+	c.Reset()
+	assert.Equal(t, e.Snapshot(), c.Snapshot(), "clone should reset to the original start")
+}
+
+func TestMessage(t *testing.T) {
+	k, err := ParseKey("B II-IV-V 02-21-12 AAA AV BS CG DL FU HZ IN KM OW RX")
+	assert.Nil(t, err)
+	e, err := k.Enigma()
+	assert.Nil(t, err)
+	tests := []struct {
+		name      string
+		positions string
+		plain     string
+		cipher    string
+	}{
+		{
+			name:      "letters",
+			positions: "BLA",
+			plain:     "AUFKLXABTEILUNGXVONXKURTINOWA",
+			cipher:    "EDPUDNRGYSZRCXNUYTPOMRMBOFKTB",
+		}, {
+			name:      "lower case letters",
+			positions: "bla",
+			plain:     "AUFKLXABTEILUNGXVONXKURTINOWA",
+			cipher:    "EDPUDNRGYSZRCXNUYTPOMRMBOFKTB",
+		}, {
+			name:      "numbers",
+			positions: "02-12-01",
+			plain:     "AUFKLXABTEILUNGXVONXKURTINOWA",
+			cipher:    "EDPUDNRGYSZRCXNUYTPOMRMBOFKTB",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := e.EncryptMessage(tt.positions, tt.plain)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.cipher, res, "encrypted message should match")
+			assert.Equal(t, "AAA", string([]rune{rune(e.rotors[2].position) + runeOffset, rune(e.rotors[1].position) + runeOffset, rune(e.rotors[0].position) + runeOffset}), "positions should be restored")
+			res, err = e.DecryptMessage(tt.positions, tt.cipher)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.plain, res, "decrypted message should match")
+		})
+	}
+
+	_, err = e.EncryptMessage("BL", "AAA")
+	assert.Error(t, err)
+	err = e.SetPositions("BLA")
+	assert.Nil(t, err)
+	res, err := e.Decode("EDPUD")
+	assert.Nil(t, err)
+	assert.Equal(t, "AUFKL", res, "decoded string should match")
+}
@@ -0,0 +1,121 @@
+package enigma
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for the classes of configuration failure, for use with errors.Is
+var (
+	ErrUnknownComponent     = errors.New("unknown component")
+	ErrInvalidPlugboard     = errors.New("invalid plugboard")
+	ErrInvalidPosition      = errors.New("invalid position")
+	ErrInvalidWiring        = errors.New("invalid wiring")
+	ErrUnencodableCharacter = errors.New("unencodable character")
+	ErrNoReflector          = errors.New("no reflector specified")
+	ErrRotorCount           = errors.New("invalid number of rotors")
+	ErrMissingConfig        = errors.New("configuration missing")
+	ErrMissingRotor         = errors.New("rotor configuration missing")
+	ErrModelMismatch        = errors.New("not possible on the model")
+	ErrInvalidKey           = errors.New("invalid key")
+	ErrDuplicateRotor       = errors.New("rotor used more than once")
+	ErrTooManyStates        = errors.New("too many states to compile")
+	ErrInvalidMessage       = errors.New("invalid message")
+	ErrInvalidIndicator     = errors.New("invalid indicator")
+)
+
+// Reasons for which a plugboard pair is rejected, given as the Reason of a PlugboardError
+var (
+	ErrTooManyPairs     = errors.New("too many pairs")
+	ErrMalformedPair    = errors.New("can only connect two letters")
+	ErrSelfConnection   = errors.New("cannot connect letter to self")
+	ErrInvalidCharacter = errors.New("must be upper case [A-Z]")
+	ErrRepeatedLetter   = errors.New("repeated letter")
+)
+
+// UnknownComponentError is returned when a rotor, reflector, entry wheel, model or stepper is not known by name
+type UnknownComponentError struct {
+	Kind string
+	Name string
+}
+
+func (e *UnknownComponentError) Error() string {
+	return fmt.Sprintf("unknown %s: %s", e.Kind, e.Name)
+}
+
+// Is matches ErrUnknownComponent
+func (e *UnknownComponentError) Is(target error) bool {
+	return target == ErrUnknownComponent
+}
+
+// PlugboardError is returned when a plugboard or reflector pair is rejected. Pair is empty when the pairs are
+// rejected as a whole, and Reason is one of the reasons such as ErrRepeatedLetter
+type PlugboardError struct {
+	Pair   string
+	Reason error
+}
+
+func (e *PlugboardError) Error() string {
+	if e.Pair == "" {
+		return fmt.Sprintf("invalid plugboard: %v", e.Reason)
+	}
+	return fmt.Sprintf("invalid plugboard pair %s: %v", e.Pair, e.Reason)
+}
+
+// Is matches ErrInvalidPlugboard
+func (e *PlugboardError) Is(target error) bool {
+	return target == ErrInvalidPlugboard
+}
+
+// Unwrap returns the reason the pair was rejected
+func (e *PlugboardError) Unwrap() error {
+	return e.Reason
+}
+
+// InvalidPositionError is returned when a start position, ring setting, notch or reflector position is outside of
+// the 26 positions of a wheel
+type InvalidPositionError struct {
+	Component string
+	Setting   string
+	Position  int
+}
+
+func (e *InvalidPositionError) Error() string {
+	if e.Component == "" {
+		return fmt.Sprintf("invalid %s %d", e.Setting, e.Position)
+	}
+	return fmt.Sprintf("invalid %s %d on %s", e.Setting, e.Position, e.Component)
+}
+
+// Is matches ErrInvalidPosition
+func (e *InvalidPositionError) Is(target error) bool {
+	return target == ErrInvalidPosition
+}
+
+// UnencodableCharacterError is returned when the input holds a character that can be neither enciphered nor
+// retained. Offset counts runes into the string given to Encode, or bytes into the buffer given to a stream
+type UnencodableCharacterError struct {
+	Rune   rune
+	Offset int
+}
+
+func (e *UnencodableCharacterError) Error() string {
+	return fmt.Sprintf("unencodable character %q at offset %d", e.Rune, e.Offset)
+}
+
+// Is matches ErrUnencodableCharacter
+func (e *UnencodableCharacterError) Is(target error) bool {
+	return target == ErrUnencodableCharacter
+}
+
+// pairString formats an int pair as letters where it can, for reporting in errors
+func pairString(pair []int) string {
+	s := []rune{}
+	for _, p := range pair {
+		if p < 0 || p > 25 {
+			return fmt.Sprint(pair)
+		}
+		s = append(s, rune(p)+runeOffset)
+	}
+	return string(s)
+}
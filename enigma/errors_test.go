@@ -0,0 +1,124 @@
+package enigma
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsIs(t *testing.T) {
+	rotors := func(names ...string) []*RotorConfiguration {
+		conf := []*RotorConfiguration{}
+		for _, n := range names {
+			conf = append(conf, &RotorConfiguration{Name: n})
+		}
+		return conf
+	}
+	tests := []struct {
+		name      string
+		rotors    []*RotorConfiguration
+		reflector string
+		plugs     string
+		opts      []Option
+		expected  []error
+	}{
+		{
+			name:      "unknown rotor",
+			rotors:    rotors(RotorI, "RotorX", RotorIII),
+			reflector: ReflectorB,
+			expected:  []error{ErrUnknownComponent},
+		}, {
+			name:      "unknown reflector",
+			rotors:    rotors(RotorI, RotorII, RotorIII),
+			reflector: "ReflectorX",
+			expected:  []error{ErrUnknownComponent},
+		}, {
+			name:     "no reflector",
+			rotors:   rotors(RotorI, RotorII, RotorIII),
+			expected: []error{ErrNoReflector},
+		}, {
+			name:      "too few rotors",
+			rotors:    rotors(RotorI, RotorII),
+			reflector: ReflectorB,
+			expected:  []error{ErrRotorCount},
+		}, {
+			name:      "repeated plug",
+			rotors:    rotors(RotorI, RotorII, RotorIII),
+			reflector: ReflectorB,
+			plugs:     "AB BC",
+			expected:  []error{ErrInvalidPlugboard, ErrRepeatedLetter},
+		}, {
+			name:      "self plug",
+			rotors:    rotors(RotorI, RotorII, RotorIII),
+			reflector: ReflectorB,
+			plugs:     "AA",
+			expected:  []error{ErrInvalidPlugboard, ErrSelfConnection},
+		}, {
+			name:      "too many plugs",
+			rotors:    rotors(RotorI, RotorII, RotorIII),
+			reflector: ReflectorB,
+			plugs:     "AB CD EF GH IJ KL MN OP QR ST UV",
+			expected:  []error{ErrInvalidPlugboard, ErrTooManyPairs},
+		}, {
+			name:      "ring setting",
+			rotors:    []*RotorConfiguration{{Name: RotorI, RingSetting: 26}, {Name: RotorII}, {Name: RotorIII}},
+			reflector: ReflectorB,
+			expected:  []error{ErrInvalidPosition},
+		}, {
+			name:      "reflector position",
+			rotors:    rotors(RotorI, RotorII, RotorIII),
+			reflector: ReflectorB,
+			opts:      []Option{WithReflectorPosition(-1)},
+			expected:  []error{ErrInvalidPosition},
+		}, {
+			name:      "wiring",
+			rotors:    []*RotorConfiguration{{Name: "custom", Configuration: "AACDEFGHIJKLMNOPQRSTUVWXYZ"}, {Name: RotorII}, {Name: RotorIII}},
+			reflector: ReflectorB,
+			expected:  []error{ErrInvalidWiring},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := New(tt.rotors, tt.reflector, tt.plugs, tt.opts...)
+			assert.Nil(t, e)
+			for _, target := range tt.expected {
+				assert.True(t, errors.Is(err, target), "%v should be %v", err, target)
+			}
+			assert.Equal(t, strings.ToLower(err.Error()[:1]), err.Error()[:1], "error should be lower case")
+		})
+	}
+}
+
+func TestErrorsAs(t *testing.T) {
+	_, err := GetReflector("ReflectorX")
+	var unknown *UnknownComponentError
+	assert.True(t, errors.As(err, &unknown))
+	assert.Equal(t, &UnknownComponentError{Kind: "reflector", Name: "ReflectorX"}, unknown)
+
+	_, err = parseStringPlugboard("AB CB")
+	var plug *PlugboardError
+	assert.True(t, errors.As(err, &plug))
+	assert.Equal(t, &PlugboardError{Pair: "CB", Reason: ErrRepeatedLetter}, plug)
+
+	_, err = NewRotorConfiguration(RotorI, 30, 0)
+	var position *InvalidPositionError
+	assert.True(t, errors.As(err, &position))
+	assert.Equal(t, &InvalidPositionError{Component: "rotor RotorI", Setting: "start position", Position: 30}, position)
+
+	e, err := New([]*RotorConfiguration{{Name: RotorIII}, {Name: RotorII}, {Name: RotorI}}, ReflectorB, "")
+	assert.Nil(t, err)
+	res, err := e.Encode("AA!A")
+	assert.Equal(t, "BD", res)
+	var char *UnencodableCharacterError
+	assert.True(t, errors.As(err, &char))
+	assert.Equal(t, &UnencodableCharacterError{Rune: '!', Offset: 2}, char)
+
+	e.Reset()
+	_, err = NewWriter(&strings.Builder{}, e).Write([]byte("AAAA?"))
+	assert.True(t, errors.As(err, &char))
+	assert.Equal(t, &UnencodableCharacterError{Rune: '?', Offset: 4}, char)
+}
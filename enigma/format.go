@@ -0,0 +1,106 @@
+package enigma
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Group sizes of transmitted ciphertext, the Kriegsmarine sending groups of 4 and the army and Luftwaffe groups of 5
+const GroupSizeNavy = 4
+const GroupSizeArmy = 5
+
+// defaultGroupsPerLine is the number of groups on each line of a message body when not given
+const defaultGroupsPerLine = 10
+
+// Group splits a text into space separated groups of size characters, as transmitted, dropping any existing
+// whitespace. A size below 1 gives groups of 5
+func Group(s string, size int) string {
+	if size < 1 {
+		size = GroupSizeArmy
+	}
+	text := []rune(Ungroup(s))
+	groups := []string{}
+	for len(text) > size {
+		groups = append(groups, string(text[:size]))
+		text = text[size:]
+	}
+	if len(text) > 0 {
+		groups = append(groups, string(text))
+	}
+	return strings.Join(groups, " ")
+}
+
+// Ungroup removes the grouping and line breaks of a received text and upper cases it, ready for decryption
+func Ungroup(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+// Message is a radio message laid out as transmitted, a header in the clear followed by the body in groups
+type Message struct {
+	// Time is the time of origin, such as "1840"
+	Time string
+	// Indicators are the groups of the header giving the key of the message, such as "WXC KCH"
+	Indicators []string
+	// Body is the ciphertext, grouped or not
+	Body string
+	// GroupSize and GroupsPerLine lay out the body, 5 and 10 when not given
+	GroupSize     int
+	GroupsPerLine int
+}
+
+// String lays out the message with a header of the time, letter count and indicator groups, such as
+// "1840 = 179 = WXC KCH =", and the body in lines of groups below
+func (m *Message) String() string {
+	body := Ungroup(m.Body)
+	header := []string{m.Time, strconv.Itoa(len([]rune(body)))}
+	if len(m.Indicators) > 0 {
+		header = append(header, strings.Join(m.Indicators, " "))
+	}
+	lines := []string{strings.Join(header, " = ") + " ="}
+	perLine := m.GroupsPerLine
+	if perLine < 1 {
+		perLine = defaultGroupsPerLine
+	}
+	groups := strings.Fields(Group(body, m.GroupSize))
+	for len(groups) > 0 {
+		n := perLine
+		if n > len(groups) {
+			n = len(groups)
+		}
+		lines = append(lines, strings.Join(groups[:n], " "))
+		groups = groups[n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseMessage reads a message laid out as by Message.String, checking the letter count of the header against the
+// body. The body is returned ungrouped
+func ParseMessage(s string) (*Message, error) {
+	lines := strings.SplitN(strings.TrimSpace(s), "\n", 2)
+	fields := strings.Split(strings.TrimSpace(lines[0]), "=")
+	if len(fields) < 3 || len(fields) > 4 || strings.TrimSpace(fields[len(fields)-1]) != "" {
+		return nil, fmt.Errorf("%w: header %q must be time = count = indicators =", ErrInvalidMessage, lines[0])
+	}
+	m := &Message{Time: strings.TrimSpace(fields[0])}
+	count, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: letter count %q", ErrInvalidMessage, strings.TrimSpace(fields[1]))
+	}
+	if len(fields) > 3 {
+		m.Indicators = strings.Fields(fields[2])
+	}
+	if len(lines) > 1 {
+		m.Body = Ungroup(lines[1])
+	}
+	if len([]rune(m.Body)) != count {
+		return nil, fmt.Errorf("%w: header counts %d letters, body has %d", ErrInvalidMessage, count, len([]rune(m.Body)))
+	}
+	return m, nil
+}
@@ -0,0 +1,108 @@
+package enigma
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		size     int
+		expected string
+	}{
+		{
+			name:     "army",
+			input:    "EDPUDNRGYSZRCXNUY",
+			size:     GroupSizeArmy,
+			expected: "EDPUD NRGYS ZRCXN UY",
+		}, {
+			name:     "navy",
+			input:    "NCZWVUSXPNYM",
+			size:     GroupSizeNavy,
+			expected: "NCZW VUSX PNYM",
+		}, {
+			name:     "regrouped",
+			input:    "NCZW VUSX\nPNYM",
+			size:     GroupSizeArmy,
+			expected: "NCZWV USXPN YM",
+		}, {
+			name:     "default size",
+			input:    "ABCDEFG",
+			expected: "ABCDE FG",
+		}, {
+			name:     "empty",
+			input:    "",
+			size:     GroupSizeArmy,
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, Group(tt.input, tt.size), "groups should match")
+		})
+	}
+}
+
+func TestUngroup(t *testing.T) {
+	assert.Equal(t, "EDPUDNRGYSZRCXN", Ungroup(" edpud NRGYS\r\n\tZRCXN \n"))
+}
+
+func TestMessageLayout(t *testing.T) {
+	m := &Message{
+		Time:          "1840",
+		Indicators:    []string{"WXC", "KCH"},
+		Body:          "EDPUDNRGYSZRCXNUYTPOMRMBOFKTBZREZKMLXLVEFGUEYSIOZVEQ",
+		GroupsPerLine: 4,
+	}
+	expected := "1840 = 52 = WXC KCH =\n" +
+		"EDPUD NRGYS ZRCXN UYTPO\n" +
+		"MRMBO FKTBZ REZKM LXLVE\n" +
+		"FGUEY SIOZV EQ"
+	assert.Equal(t, expected, m.String(), "message layout should match")
+
+	parsed, err := ParseMessage(expected)
+	assert.Nil(t, err)
+	assert.Equal(t, &Message{Time: m.Time, Indicators: m.Indicators, Body: m.Body}, parsed)
+
+	m = &Message{Time: "0915", Body: "NCZW VUSX", GroupSize: GroupSizeNavy}
+	assert.Equal(t, "0915 = 8 =\nNCZW VUSX", m.String(), "message without indicators should match")
+	parsed, err = ParseMessage(m.String())
+	assert.Nil(t, err)
+	assert.Equal(t, &Message{Time: "0915", Body: "NCZWVUSX"}, parsed)
+}
+
+func TestInvalidParseMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "no header",
+			input: "EDPUD NRGYS",
+		}, {
+			name:  "count",
+			input: "1840 = 11 = WXC KCH =\nEDPUD NRGYS",
+		}, {
+			name:  "count not a number",
+			input: "1840 = ten = WXC KCH =\nEDPUD NRGYS",
+		}, {
+			name:  "unterminated header",
+			input: "1840 = 10 = WXC KCH\nEDPUD NRGYS",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := ParseMessage(tt.input)
+			assert.Nil(t, m)
+			assert.True(t, errors.Is(err, ErrInvalidMessage), "%v should be invalid message", err)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package enigma
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// kenngruppeLength is the length of the key identification group, three random letters and a two letter discriminant
+const kenngruppeLength = 5
+
+// RandomSetting returns count random letters, such as for the Grundstellung or message key chosen by the operator.
+// A nil source uses the default source of math/rand
+func RandomSetting(count int, r *rand.Rand) string {
+	intn := rand.Intn
+	if r != nil {
+		intn = r.Intn
+	}
+	letters := make([]rune, count)
+	for i := range letters {
+		letters[i] = rune(intn(26)) + runeOffset
+	}
+	return string(letters)
+}
+
+// NewKenngruppe returns a key identification group, three random letters followed by the two letter discriminant
+// of the key in use. A nil source uses the default source of math/rand
+func NewKenngruppe(discriminant string, r *rand.Rand) (string, error) {
+	discriminant, err := parseLetters(ErrInvalidIndicator, "discriminant", discriminant, kenngruppeLength-3)
+	if err != nil {
+		return "", err
+	}
+	return RandomSetting(3, r) + discriminant, nil
+}
+
+// EncryptWithIndicator enciphers a message with the procedure used from May 1940. The machine is set up with the
+// daily key, whose positions are not used, and the message key is enciphered once from the Grundstellung chosen
+// by the operator. Both go in the clear as the indicator groups of the message, whose body is the Kenngruppe
+// followed by the text enciphered from the message key. The time of the message is left for the caller to set
+func EncryptWithIndicator(daily *Key, grundstellung, messageKey, kenngruppe, text string, opts ...Option) (*Message, error) {
+	e, err := daily.Enigma(opts...)
+	if err != nil {
+		return nil, err
+	}
+	grundstellung, err = parseLetters(ErrInvalidKey, "grundstellung", grundstellung, len(daily.Rotors))
+	if err != nil {
+		return nil, err
+	}
+	messageKey, err = parseLetters(ErrInvalidKey, "message key", messageKey, len(daily.Rotors))
+	if err != nil {
+		return nil, err
+	}
+	kenngruppe, err = parseLetters(ErrInvalidIndicator, "kenngruppe", kenngruppe, kenngruppeLength)
+	if err != nil {
+		return nil, err
+	}
+	enciphered, err := e.EncryptMessage(grundstellung, messageKey)
+	if err != nil {
+		return nil, err
+	}
+	body, err := e.EncryptMessage(messageKey, text)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		Indicators: []string{grundstellung, enciphered},
+		Body:       kenngruppe + Ungroup(body),
+	}, nil
+}
+
+// DecryptWithIndicator deciphers a message sent with the procedure used from May 1940, recovering the message key
+// from the indicator groups of the header. The Kenngruppe must carry the discriminant of the daily key, unless the
+// discriminant is left empty
+func DecryptWithIndicator(daily *Key, m *Message, discriminant string, opts ...Option) (messageKey, text string, err error) {
+	e, err := daily.Enigma(opts...)
+	if err != nil {
+		return "", "", err
+	}
+	if len(m.Indicators) != 2 {
+		return "", "", fmt.Errorf("%w: requires the Grundstellung and message key groups: %v", ErrInvalidIndicator, m.Indicators)
+	}
+	grundstellung, err := parseLetters(ErrInvalidIndicator, "grundstellung", m.Indicators[0], len(daily.Rotors))
+	if err != nil {
+		return "", "", err
+	}
+	enciphered, err := parseLetters(ErrInvalidIndicator, "message key", m.Indicators[1], len(daily.Rotors))
+	if err != nil {
+		return "", "", err
+	}
+	body := Ungroup(m.Body)
+	if len(body) < kenngruppeLength {
+		return "", "", fmt.Errorf("%w: body is shorter than the kenngruppe", ErrInvalidMessage)
+	}
+	if discriminant != "" && body[3:kenngruppeLength] != strings.ToUpper(discriminant) {
+		return "", "", fmt.Errorf("%w: kenngruppe %s is not of the discriminant %s", ErrInvalidIndicator, body[:kenngruppeLength], discriminant)
+	}
+	messageKey, err = e.EncryptMessage(grundstellung, enciphered)
+	if err != nil {
+		return "", "", err
+	}
+	text, err = e.DecryptMessage(messageKey, body[kenngruppeLength:])
+	if err != nil {
+		return "", "", err
+	}
+	return messageKey, text, nil
+}
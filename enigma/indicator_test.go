@@ -0,0 +1,97 @@
+package enigma
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithIndicator(t *testing.T) {
+	// Operation Barbarossa message of 7 July 1941, first part, with the header as sent
+	daily, err := ParseKey("B II-IV-V 02-21-12 AAA AV BS CG DL FU HZ IN KM OW RX")
+	assert.Nil(t, err)
+	plain := "AUFKLXABTEILUNGXVONXKURTINOWAXKURTINOWAXNORDWESTLXSEBEZXSEBEZXUAFFLIEGERSTRASZERIQTUNGXDUBROWKIXDUBROWKIXOPOTSCHKAXOPOTSCHKAXUMXEINSAQTDREINULLXUHRANGETRETENXANGRIFFXINFXRGTX"
+	m, err := EncryptWithIndicator(daily, "WXC", "BLA", "RFUGZ", plain)
+	assert.Nil(t, err)
+	m.Time = "1840"
+	assert.Equal(t, []string{"WXC", "KCH"}, m.Indicators, "indicator groups should match")
+	assert.Equal(t, "RFUGZEDPUDNRGYSZRCXN", m.Body[:20], "body should start with the kenngruppe")
+	assert.Equal(t, "1840 = 179 = WXC KCH =", m.String()[:22], "header should match")
+
+	received, err := ParseMessage(m.String())
+	assert.Nil(t, err)
+	messageKey, text, err := DecryptWithIndicator(daily, received, "GZ")
+	assert.Nil(t, err)
+	assert.Equal(t, "BLA", messageKey, "message key should be recovered")
+	assert.Equal(t, plain, text, "text should match")
+
+	messageKey, _, err = DecryptWithIndicator(daily, received, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "BLA", messageKey, "discriminant should not be checked when empty")
+}
+
+func TestInvalidWithIndicator(t *testing.T) {
+	daily, err := ParseKey("B I-II-III 01-01-01 AAA")
+	assert.Nil(t, err)
+	tests := []struct {
+		name          string
+		grundstellung string
+		messageKey    string
+		kenngruppe    string
+		expected      error
+	}{
+		{
+			name:          "grundstellung",
+			grundstellung: "WX",
+			messageKey:    "BLA",
+			kenngruppe:    "RFUGZ",
+			expected:      ErrInvalidKey,
+		}, {
+			name:          "message key",
+			grundstellung: "WXC",
+			messageKey:    "B1A",
+			kenngruppe:    "RFUGZ",
+			expected:      ErrInvalidKey,
+		}, {
+			name:          "kenngruppe",
+			grundstellung: "WXC",
+			messageKey:    "BLA",
+			kenngruppe:    "RFUG",
+			expected:      ErrInvalidIndicator,
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := EncryptWithIndicator(daily, tt.grundstellung, tt.messageKey, tt.kenngruppe, "AAAAA")
+			assert.Nil(t, m)
+			assert.True(t, errors.Is(err, tt.expected), "%v should be %v", err, tt.expected)
+		})
+	}
+
+	m, err := EncryptWithIndicator(daily, "WXC", "BLA", "RFUGZ", "AAAAA")
+	assert.Nil(t, err)
+	_, _, err = DecryptWithIndicator(daily, m, "AB")
+	assert.True(t, errors.Is(err, ErrInvalidIndicator), "%v should be invalid indicator", err)
+	_, _, err = DecryptWithIndicator(daily, &Message{Indicators: []string{"WXC"}, Body: m.Body}, "GZ")
+	assert.True(t, errors.Is(err, ErrInvalidIndicator), "%v should be invalid indicator", err)
+	_, _, err = DecryptWithIndicator(daily, &Message{Indicators: m.Indicators, Body: "RFU"}, "")
+	assert.True(t, errors.Is(err, ErrInvalidMessage), "%v should be invalid message", err)
+}
+
+func TestNewKenngruppe(t *testing.T) {
+	k, err := NewKenngruppe("gz", rand.New(rand.NewSource(1)))
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[A-Z]{3}GZ$", k)
+	assert.Equal(t, k, func() string {
+		k, _ := NewKenngruppe("GZ", rand.New(rand.NewSource(1)))
+		return k
+	}(), "kenngruppe should be reproducible from the source")
+	assert.Regexp(t, "^[A-Z]{3}$", RandomSetting(3, nil))
+
+	_, err = NewKenngruppe("GZA", nil)
+	assert.True(t, errors.Is(err, ErrInvalidIndicator))
+}
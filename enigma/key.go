@@ -0,0 +1,215 @@
+package enigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Key is the complete setting of a machine as operators wrote it, such as "B III-II-I 01-02-03 KDO AZ BC XT", giving
+// the reflector, the rotor order, ring settings and start positions from left to right, and the plugboard. A rewired
+// UKW-D is written with its pairs in Bletchley notation, "D:AC-BO-DE-FG-HI-KL-MN-PQ-RS-TU-VW-XZ"
+type Key struct {
+	Reflector string
+	// ReflectorWiring holds the space separated pairs of a UKW-D in Bletchley notation, empty for other reflectors
+	ReflectorWiring string
+	// Rotors are ordered from left to right, the reverse of the order taken by New
+	Rotors []string
+	// RingSettings and Positions are ordered from left to right and count from 0 for A
+	RingSettings []int
+	Positions    []int
+	Plugboard    string
+}
+
+// ParseKey converts a compact key string into a Key, validating each of its parts. Rotors and reflectors are
+// written without their prefix, "III" for RotorIII and "BThin" for ReflectorBThin, ring settings as numbers
+// "01" to "26" or letters and positions as letters
+func ParseKey(s string) (*Key, error) {
+	parts := strings.Fields(s)
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("%w %q, requires reflector, rotors, ring settings and positions", ErrInvalidKey, s)
+	}
+	reflector, wiring, err := parseKeyReflector(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	rotors := []string{}
+	for _, r := range strings.Split(parts[1], "-") {
+		name, err := parseKeyComponent(r, "Rotor", getRotor)
+		if err != nil {
+			return nil, err
+		}
+		rotors = append(rotors, name)
+	}
+	rings, err := parseKeySettings(parts[2], len(rotors))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ring settings: %w", err)
+	}
+	positions, err := parseKeySettings(parts[3], len(rotors))
+	if err != nil {
+		return nil, fmt.Errorf("invalid positions: %w", err)
+	}
+	plugs := strings.Join(parts[4:], " ")
+	_, err = parseStringPlugboard(plugs)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		Reflector:       reflector,
+		ReflectorWiring: wiring,
+		Rotors:          rotors,
+		RingSettings:    rings,
+		Positions:       positions,
+		Plugboard:       plugs,
+	}, nil
+}
+
+// parseKeyReflector resolves the reflector of a key, with the pairs of a UKW-D written after a colon
+func parseKeyReflector(s string) (string, string, error) {
+	prefix := strings.TrimPrefix(ReflectorD, "Reflector") + ":"
+	if !strings.HasPrefix(s, prefix) {
+		reflector, err := parseKeyComponent(s, "Reflector", getReflector)
+		return reflector, "", err
+	}
+	wiring := strings.ReplaceAll(strings.TrimPrefix(s, prefix), "-", " ")
+	if _, err := NewReflectorD(wiring, NotationBletchley); err != nil {
+		return "", "", err
+	}
+	return ReflectorD, wiring, nil
+}
+
+// parseKeyComponent resolves a component written without its prefix, falling back to the name as written for
+// components registered without the prefix
+func parseKeyComponent(s, prefix string, get func(string) (string, error)) (string, error) {
+	if _, err := get(prefix + s); err == nil {
+		return prefix + s, nil
+	}
+	if _, err := get(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// parseKeySettings converts settings written either as dash separated letters or numbers, "01-02-03", or as
+// consecutive letters, "KDO", to positions. Letters may be in either case
+func parseKeySettings(s string, count int) ([]int, error) {
+	s = strings.ToUpper(s)
+	settings := strings.Split(s, "-")
+	if len(settings) == 1 && len(s) == count && strings.Trim(s, entryWheelABC) == "" {
+		settings = strings.Split(s, "")
+	}
+	if len(settings) != count {
+		return nil, fmt.Errorf("%w: %q has %d settings for %d rotors", ErrRotorCount, s, len(settings), count)
+	}
+	res := []int{}
+	for _, setting := range settings {
+		n, err := ParseSetting(setting)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// String formats the key in its compact form, with ring settings as numbers and positions as letters. Settings
+// that are missing or out of range are written as ?, which ParseKey rejects
+func (k *Key) String() string {
+	count := len(k.Rotors)
+	if len(k.RingSettings) > count {
+		count = len(k.RingSettings)
+	}
+	if len(k.Positions) > count {
+		count = len(k.Positions)
+	}
+	rotors := []string{}
+	rings := []string{}
+	positions := []rune{}
+	for i := 0; i < count; i++ {
+		rotor, ring, position := "?", "??", '?'
+		if i < len(k.Rotors) {
+			rotor = strings.TrimPrefix(k.Rotors[i], "Rotor")
+		}
+		if i < len(k.RingSettings) && 0 <= k.RingSettings[i] && k.RingSettings[i] < 26 {
+			ring = fmt.Sprintf("%02d", k.RingSettings[i]+1)
+		}
+		if i < len(k.Positions) && 0 <= k.Positions[i] && k.Positions[i] < 26 {
+			position = rune(k.Positions[i]) + runeOffset
+		}
+		rotors = append(rotors, rotor)
+		rings = append(rings, ring)
+		positions = append(positions, position)
+	}
+	reflector := strings.TrimPrefix(k.Reflector, "Reflector")
+	if k.ReflectorWiring != "" {
+		reflector += ":" + strings.ReplaceAll(k.ReflectorWiring, " ", "-")
+	}
+	parts := []string{
+		reflector,
+		strings.Join(rotors, "-"),
+		strings.Join(rings, "-"),
+		string(positions),
+	}
+	if k.Plugboard != "" {
+		parts = append(parts, k.Plugboard)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Enigma instantiates the machine described by the key
+func (k *Key) Enigma(opts ...Option) (*Enigma, error) {
+	if err := k.check(); err != nil {
+		return nil, err
+	}
+	confs := []*RotorConfiguration{}
+	for i := len(k.Rotors) - 1; i >= 0; i-- {
+		c, err := NewRotorConfiguration(k.Rotors[i], k.Positions[i], k.RingSettings[i])
+		if err != nil {
+			return nil, err
+		}
+		confs = append(confs, c)
+	}
+	reflector := k.Reflector
+	if k.ReflectorWiring != "" {
+		if reflector != ReflectorD {
+			return nil, fmt.Errorf("%w: reflector %s cannot be rewired", ErrInvalidWiring, reflector)
+		}
+		ukwd, err := NewReflectorD(k.ReflectorWiring, NotationBletchley)
+		if err != nil {
+			return nil, err
+		}
+		reflector = ""
+		opts = append([]Option{WithReflector(ukwd)}, opts...)
+	}
+	return New(confs, reflector, k.Plugboard, opts...)
+}
+
+// check validates there is a ring setting and position from 0 to 25 for each rotor, as needed to instantiate the key
+func (k *Key) check() error {
+	if len(k.RingSettings) != len(k.Rotors) || len(k.Positions) != len(k.Rotors) {
+		return fmt.Errorf("%w: requires a ring setting and position for each of %d rotors", ErrInvalidKey, len(k.Rotors))
+	}
+	for i := range k.Rotors {
+		if k.RingSettings[i] < 0 || k.RingSettings[i] > 25 || k.Positions[i] < 0 || k.Positions[i] > 25 {
+			return fmt.Errorf("%w: setting out of range for rotor %s", ErrInvalidKey, k.Rotors[i])
+		}
+	}
+	return nil
+}
+
+// Key returns the current setting of the machine as a Key, with the rotors at their current positions. Entry wheel,
+// stepping and reflector position are not part of the key, nor can a custom reflector other than a UKW-D be written
+func (e *Enigma) Key() *Key {
+	k := &Key{
+		Reflector: e.reflector.Name,
+		Plugboard: e.plugs.String(),
+	}
+	if e.reflector.Name == ReflectorD {
+		k.ReflectorWiring = reflectorPairs(e.reflector)
+	}
+	for i := len(e.rotors) - 1; i >= 0; i-- {
+		k.Rotors = append(k.Rotors, e.rotors[i].Name)
+		k.RingSettings = append(k.RingSettings, e.rotors[i].ringSetting)
+		k.Positions = append(k.Positions, e.rotors[i].position)
+	}
+	return k
+}
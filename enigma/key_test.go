@@ -0,0 +1,181 @@
+package enigma
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *Key
+		output   string
+	}{
+		{
+			name:  "base",
+			input: "B III-II-I 01-02-03 KDO AZ BC XT",
+			expected: &Key{
+				Reflector:    ReflectorB,
+				Rotors:       []string{RotorIII, RotorII, RotorI},
+				RingSettings: []int{0, 1, 2},
+				Positions:    []int{10, 3, 14},
+				Plugboard:    "AZ BC XT",
+			},
+			output: "B III-II-I 01-02-03 KDO AZ BC XT",
+		}, {
+			name:  "no plugboard",
+			input: "C I-II-III 26-01-01 ZZZ",
+			expected: &Key{
+				Reflector:    ReflectorC,
+				Rotors:       []string{RotorI, RotorII, RotorIII},
+				RingSettings: []int{25, 0, 0},
+				Positions:    []int{25, 25, 25},
+				Plugboard:    "",
+			},
+			output: "C I-II-III 26-01-01 ZZZ",
+		}, {
+			name:  "letter settings",
+			input: "BThin Beta-II-IV-I A-A-A-V V-J-N-A AT BL",
+			expected: &Key{
+				Reflector:    ReflectorBThin,
+				Rotors:       []string{RotorBeta, RotorII, RotorIV, RotorI},
+				RingSettings: []int{0, 0, 0, 21},
+				Positions:    []int{21, 9, 13, 0},
+				Plugboard:    "AT BL",
+			},
+			output: "BThin Beta-II-IV-I 01-01-01-22 VJNA AT BL",
+		}, {
+			name:  "lower case settings",
+			input: "B III-II-I a-b-c kdo",
+			expected: &Key{
+				Reflector:    ReflectorB,
+				Rotors:       []string{RotorIII, RotorII, RotorI},
+				RingSettings: []int{0, 1, 2},
+				Positions:    []int{10, 3, 14},
+			},
+			output: "B III-II-I 01-02-03 KDO",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			k, err := ParseKey(tt.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, k, "key should match")
+			assert.Equal(t, tt.output, k.String(), "formatted key should match")
+			res, err := ParseKey(k.String())
+			assert.Nil(t, err)
+			assert.Equal(t, k, res, "formatted key should parse to the same key")
+		})
+	}
+}
+
+func TestInvalidParseKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "missing positions",
+			input: "B III-II-I 01-02-03",
+		}, {
+			name:  "unknown reflector",
+			input: "Q III-II-I 01-02-03 KDO",
+		}, {
+			name:  "unknown rotor",
+			input: "B III-II-X 01-02-03 KDO",
+		}, {
+			name:  "ring setting out of range",
+			input: "B III-II-I 01-02-27 KDO",
+		}, {
+			name:  "too few positions",
+			input: "B III-II-I 01-02-03 KD",
+		}, {
+			name:  "invalid plugboard",
+			input: "B III-II-I 01-02-03 KDO AZ AB",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			k, err := ParseKey(tt.input)
+			assert.Nil(t, k)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyEnigma(t *testing.T) {
+	k, err := ParseKey("BThin Beta-II-IV-I 01-01-01-22 VJNA AT BL DF GJ HM NW OP QY RZ VX")
+	assert.Nil(t, err)
+	e, err := k.Enigma()
+	assert.Nil(t, err)
+	res, err := e.Encode("NCZWVUSXPNYMINHZXMQX")
+	assert.Nil(t, err)
+	assert.Equal(t, "VONVONJLOOKSJHFFTTTE", res, "decrypted message should match")
+
+	k, err = ParseKey("B I-II-III 01-02-07 AXF XT AZ BC")
+	assert.Nil(t, err)
+	e, err = k.Enigma()
+	assert.Nil(t, err)
+	assert.Equal(t, "B I-II-III 01-02-07 AXF AZ BC TX", e.Key().String(), "machine key should match")
+	res, err = e.Encode("AAAAA")
+	assert.Nil(t, err)
+	assert.Equal(t, "JTUJZ", res, "encoded message should match")
+}
+
+func TestKeyReflectorD(t *testing.T) {
+	ukwd := "AC BO DE FG HI KL MN PQ RS TU VW XZ"
+	r, err := NewReflectorD(ukwd, NotationBletchley)
+	assert.Nil(t, err)
+	conf := []*RotorConfiguration{
+		{Name: RotorIII, Position: 4},
+		{Name: RotorII, RingSetting: 3},
+		{Name: RotorI},
+	}
+	e, err := New(conf, "", "AZ", WithReflector(r))
+	assert.Nil(t, err)
+	k := e.Key()
+	assert.Equal(t, "D:AC-BO-DE-FG-HI-KL-MN-PQ-RS-TU-VW-XZ I-II-III 01-04-01 AAE AZ", k.String(), "key should match")
+
+	parsed, err := ParseKey(k.String())
+	assert.Nil(t, err)
+	assert.Equal(t, k, parsed, "parsed key should match")
+	rebuilt, err := parsed.Enigma()
+	assert.Nil(t, err)
+	expected, err := e.Encode("LUFTWAFFE")
+	assert.Nil(t, err)
+	res, err := rebuilt.Encode("LUFTWAFFE")
+	assert.Nil(t, err)
+	assert.Equal(t, expected, res, "rebuilt machine should match")
+
+	_, err = ParseKey("D:AC-BO-DE I-II-III 01-01-01 AAA")
+	assert.Error(t, err)
+	_, err = (&Key{Reflector: ReflectorB, ReflectorWiring: ukwd, Rotors: []string{RotorI, RotorII, RotorIII}, RingSettings: []int{0, 0, 0}, Positions: []int{0, 0, 0}}).Enigma()
+	assert.True(t, errors.Is(err, ErrInvalidWiring), "%v should be invalid wiring", err)
+}
+
+func TestInvalidKeyString(t *testing.T) {
+	k := &Key{Reflector: ReflectorB, Rotors: []string{RotorI, RotorII, RotorIII}}
+	assert.Equal(t, "B I-II-III ??-??-?? ???", k.String(), "missing settings should be marked")
+	e, err := k.Enigma()
+	assert.Nil(t, e)
+	assert.True(t, errors.Is(err, ErrInvalidKey), "%v should be invalid key", err)
+	_, err = ParseKey(k.String())
+	assert.Error(t, err)
+
+	k = &Key{Reflector: ReflectorB, Rotors: []string{RotorI, RotorII}, RingSettings: []int{0, 30, 1}, Positions: []int{10, 3}}
+	assert.Equal(t, "B I-II-? 01-??-02 KD?", k.String(), "missing and invalid settings should be marked")
+	_, err = k.Enigma()
+	assert.True(t, errors.Is(err, ErrInvalidKey), "%v should be invalid key", err)
+
+	k = &Key{Reflector: ReflectorB, Rotors: []string{RotorI, RotorII, RotorIII}, RingSettings: []int{0, 30, 1}, Positions: []int{10, 3, 14}}
+	assert.Equal(t, "B I-II-III 01-??-02 KDO", k.String(), "invalid settings should be marked")
+	_, err = k.Enigma()
+	assert.True(t, errors.Is(err, ErrInvalidKey), "%v should be invalid key", err)
+}
@@ -0,0 +1,28 @@
+package enigma
+
+// Logger receives debug events of the machine as key and value pairs, for construction and for each traversal. It
+// is satisfied by *slog.Logger, and machines are silent when none is given
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// WithLogger sets the logger to receive the debug events of the machine. Clones of the machine share the logger
+func WithLogger(l Logger) Option {
+	return func(e *Enigma) error {
+		e.logger = l
+		return nil
+	}
+}
+
+// logConstruction reports the components of a newly instantiated machine
+func (e *Enigma) logConstruction() {
+	if e.logger == nil {
+		return
+	}
+	for i, r := range e.rotors {
+		e.logger.Debug("rotor instantiated", "index", i, "name", r.Name, "configuration", r.configuration(), "position", r.position, "ringSetting", r.ringSetting)
+	}
+	e.logger.Debug("reflector instantiated", "name", e.reflector.Name, "configuration", e.reflector.configuration(), "position", e.reflector.position)
+	e.logger.Debug("entry wheel instantiated", "name", e.entry.Name, "configuration", e.entry.configuration())
+	e.logger.Debug("plugboard instantiated", "pairs", e.plugs.String())
+}
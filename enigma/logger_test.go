@@ -0,0 +1,43 @@
+package enigma
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) {
+	l.messages = append(l.messages, strings.TrimSpace(fmt.Sprintln(append([]interface{}{msg}, args...)...)))
+}
+
+func TestLogger(t *testing.T) {
+	l := &recordingLogger{}
+	k, err := ParseKey("B I-II-III 01-01-01 AAA AZ")
+	assert.Nil(t, err)
+	e, err := k.Enigma(WithLogger(l))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"rotor instantiated index 0 name RotorIII configuration BDFHJLCPRTXVZNYEIWGAKMUSQO position 0 ringSetting 0",
+		"rotor instantiated index 1 name RotorII configuration AJDKSIRUXBLHWTMCQGZNPYFVOE position 0 ringSetting 0",
+		"rotor instantiated index 2 name RotorI configuration EKMFLGDQVZNTOWYHXUSPAIBRCJ position 0 ringSetting 0",
+		"reflector instantiated name ReflectorB configuration YRUHQSLDPXNGOKMIEBFZCWVJAT position 0",
+		"entry wheel instantiated name EntryWheelABC configuration ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+		"plugboard instantiated pairs AZ",
+	}, l.messages, "construction events should match")
+
+	l.messages = nil
+	res, err := e.Encode("Z")
+	assert.Nil(t, err)
+	assert.Equal(t, "B", res)
+	assert.Equal(t, []string{
+		"plugboard traversed in Z out A",
+		"plugboard traversed in B out B",
+		"key encoded in Z out B positions AAB",
+	}, l.messages, "traversal events should match")
+}
@@ -0,0 +1,225 @@
+package enigma
+
+import (
+	"fmt"
+)
+
+// Models Names of the historical enigma machines available as presets
+const ModelEnigmaI = "EnigmaI"
+const ModelM3 = "M3"
+const ModelM4 = "M4"
+const ModelD = "D"
+const ModelK = "K"
+const ModelT = "T"
+const ModelG = "G"
+
+// Model describes a historical enigma machine and the configurations it could physically have
+type Model struct {
+	Name string
+	// Rotors are those that may be fitted to any of the stepping positions
+	Rotors []string
+	// GreekRotors are those that may be fitted to the additional non-stepping position, if the model has one
+	GreekRotors []string
+	Reflectors  []string
+	EntryWheel  string
+	// RotorCount is the number of stepping positions
+	RotorCount        int
+	Stepper           Stepper
+	Plugboard         bool
+	RingSettable      bool
+	ReflectorSettable bool
+}
+
+var militaryRotors = []string{RotorI, RotorII, RotorIII, RotorIV, RotorV, RotorVI, RotorVII, RotorVIII}
+var tirpitzRotors = []string{RotorTI, RotorTII, RotorTIII, RotorTIV, RotorTV, RotorTVI, RotorTVII, RotorTVIII}
+
+var models = map[string]*Model{
+	ModelEnigmaI: {
+		Name:         ModelEnigmaI,
+		Rotors:       militaryRotors[:5:5],
+		Reflectors:   []string{ReflectorA, ReflectorB, ReflectorC, ReflectorD},
+		EntryWheel:   EntryWheelABC,
+		RotorCount:   3,
+		Stepper:      LeverStepper{},
+		Plugboard:    true,
+		RingSettable: true,
+	},
+	ModelM3: {
+		Name:         ModelM3,
+		Rotors:       militaryRotors,
+		Reflectors:   []string{ReflectorB, ReflectorC},
+		EntryWheel:   EntryWheelABC,
+		RotorCount:   3,
+		Stepper:      LeverStepper{},
+		Plugboard:    true,
+		RingSettable: true,
+	},
+	ModelM4: {
+		Name:         ModelM4,
+		Rotors:       militaryRotors,
+		GreekRotors:  []string{RotorBeta, RotorGamma},
+		Reflectors:   []string{ReflectorBThin, ReflectorCThin},
+		EntryWheel:   EntryWheelABC,
+		RotorCount:   3,
+		Stepper:      LeverStepper{},
+		Plugboard:    true,
+		RingSettable: true,
+	},
+	ModelD: {
+		Name:              ModelD,
+		Rotors:            []string{RotorDI, RotorDII, RotorDIII},
+		Reflectors:        []string{ReflectorCommercial},
+		EntryWheel:        EntryWheelQWERTZ,
+		RotorCount:        3,
+		Stepper:           LeverStepper{},
+		RingSettable:      true,
+		ReflectorSettable: true,
+	},
+	ModelK: {
+		Name:              ModelK,
+		Rotors:            []string{RotorKI, RotorKII, RotorKIII},
+		Reflectors:        []string{ReflectorCommercial},
+		EntryWheel:        EntryWheelQWERTZ,
+		RotorCount:        3,
+		Stepper:           LeverStepper{},
+		RingSettable:      true,
+		ReflectorSettable: true,
+	},
+	ModelT: {
+		Name:              ModelT,
+		Rotors:            tirpitzRotors,
+		Reflectors:        []string{ReflectorT},
+		EntryWheel:        EntryWheelT,
+		RotorCount:        3,
+		Stepper:           LeverStepper{},
+		RingSettable:      true,
+		ReflectorSettable: true,
+	},
+	ModelG: {
+		Name:              ModelG,
+		Rotors:            []string{RotorGI, RotorGII, RotorGIII},
+		Reflectors:        []string{ReflectorG},
+		EntryWheel:        EntryWheelQWERTZ,
+		RotorCount:        3,
+		Stepper:           CogStepper{},
+		RingSettable:      true,
+		ReflectorSettable: true,
+	},
+}
+
+// GetModel takes a model name and returns a copy of the description of the historical machine, which may be changed
+// freely
+func GetModel(name string) (*Model, error) {
+	m, ok := models[name]
+	if !ok {
+		return nil, &UnknownComponentError{Kind: "model", Name: name}
+	}
+	c := *m
+	c.Rotors = append([]string(nil), m.Rotors...)
+	c.GreekRotors = append([]string(nil), m.GreekRotors...)
+	c.Reflectors = append([]string(nil), m.Reflectors...)
+	return &c, nil
+}
+
+// NewFromModel instantiates an enigma machine of a historical model, rejecting configurations the model could
+// not physically have. Rotors are specified from right to left, any greek rotor last
+func NewFromModel(name string, rotorConfs []*RotorConfiguration, reflector string, plugs string, opts ...Option) (*Enigma, error) {
+	m, err := GetModel(name)
+	if err != nil {
+		return nil, err
+	}
+	err = m.Validate(rotorConfs, reflector, plugs)
+	if err != nil {
+		return nil, err
+	}
+	e, err := New(rotorConfs, reflector, plugs, append(opts, WithStepper(m.Stepper), WithEntryWheel(m.EntryWheel))...)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(m.Reflectors, e.reflector.Name) {
+		return nil, fmt.Errorf("%w: reflector %s cannot be fitted to the %s", ErrModelMismatch, e.reflector.Name, m.Name)
+	}
+	if !m.ReflectorSettable && e.reflector.position != 0 {
+		return nil, fmt.Errorf("%w: the %s has no settable reflector", ErrModelMismatch, m.Name)
+	}
+	e.model = m.Name
+	return e, nil
+}
+
+// NewM4 instantiates a Kriegsmarine M4 enigma machine. Rotors are specified from right to left, the fourth being
+// the greek rotor, Beta or Gamma
+func NewM4(rotorConfs []*RotorConfiguration, reflector string, plugs string, opts ...Option) (*Enigma, error) {
+	return NewFromModel(ModelM4, rotorConfs, reflector, plugs, opts...)
+}
+
+// Validate checks that a configuration is one the model could physically have. An empty reflector name is left
+// to be supplied by WithReflector
+func (m *Model) Validate(rotorConfs []*RotorConfiguration, reflector string, plugs string) error {
+	problems := append(m.problems(rotorConfs, reflector, plugs), duplicateProblems(rotorConfs)...)
+	if len(problems) > 0 {
+		return problems[0].Err
+	}
+	return nil
+}
+
+// problems lists every way in which a configuration, with rotors from right to left, is not one the model could
+// physically have, naming the fields at fault as in configuration files
+func (m *Model) problems(rotorConfs []*RotorConfiguration, reflector string, plugs string) []Problem {
+	count := m.RotorCount
+	if len(m.GreekRotors) > 0 {
+		count++
+	}
+	if len(rotorConfs) != count {
+		return []Problem{{
+			Field: "rotors",
+			Err:   fmt.Errorf("%w: the %s requires exactly %d rotors: %d", ErrRotorCount, m.Name, count, len(rotorConfs)),
+		}}
+	}
+	problems := []Problem{}
+	for i, r := range rotorConfs[:m.RotorCount] {
+		if r != nil && !contains(m.Rotors, r.Name) {
+			problems = append(problems, Problem{
+				Field: rotorField(rotorConfs, i, "name"),
+				Err:   fmt.Errorf("%w: rotor %s cannot be fitted to position %d of the %s", ErrModelMismatch, r.Name, i, m.Name),
+			})
+		}
+	}
+	if g := rotorConfs[len(rotorConfs)-1]; len(m.GreekRotors) > 0 && g != nil && !contains(m.GreekRotors, g.Name) {
+		problems = append(problems, Problem{
+			Field: rotorField(rotorConfs, m.RotorCount, "name"),
+			Err:   fmt.Errorf("%w: rotor %s cannot be fitted to the greek position of the %s", ErrModelMismatch, g.Name, m.Name),
+		})
+	}
+	if !m.RingSettable {
+		for i, r := range rotorConfs {
+			if r != nil && r.RingSetting != 0 {
+				problems = append(problems, Problem{
+					Field: rotorField(rotorConfs, i, "ringSetting"),
+					Err:   fmt.Errorf("%w: the %s has no settable ring: rotor %s", ErrModelMismatch, m.Name, r.Name),
+				})
+			}
+		}
+	}
+	if reflector != "" && !contains(m.Reflectors, reflector) {
+		problems = append(problems, Problem{
+			Field: "reflector.name",
+			Err:   fmt.Errorf("%w: reflector %s cannot be fitted to the %s", ErrModelMismatch, reflector, m.Name),
+		})
+	}
+	if !m.Plugboard && plugs != "" {
+		problems = append(problems, Problem{
+			Field: "plugboard",
+			Err:   fmt.Errorf("%w: the %s has no plugboard", ErrModelMismatch, m.Name),
+		})
+	}
+	return problems
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
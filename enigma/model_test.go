@@ -0,0 +1,492 @@
+package enigma
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewM4(t *testing.T) {
+	tests := []struct {
+		name      string
+		rotors    []*RotorConfiguration
+		reflector string
+		plugs     string
+		input     string
+		expected  string
+	}{
+		{
+			// U-264 message of 25 November 1942, decrypted by the M4 project in 2006
+			name: "u264",
+			rotors: []*RotorConfiguration{
+				{Name: RotorI, Position: 0, RingSetting: 21},
+				{Name: RotorIV, Position: 13},
+				{Name: RotorII, Position: 9},
+				{Name: RotorBeta, Position: 21},
+			},
+			reflector: ReflectorBThin,
+			plugs:     "AT BL DF GJ HM NW OP QY RZ VX",
+			input:     "NCZWVUSXPNYMINHZXMQXSFWXWLKJAHSHNMCOCCAKUQPMKCSMHKSEINJUSBLKIOSXCKUBHMLLXCSJUSRRDVKOHULXWCCBGVLIYXEOAHXRHKKFVDREWEZLXOBAFGYUJQUKGRTVUKAMEURBVEKSUHHVOYHABCJWMAKLFKLMYFVNRIZRVVRTKOFDANJMOLBGFFLEOPRGTFLVRHOWOPBEKVWMUQFMPWPARMFHAGKXIIBG",
+			expected:  "VONVONJLOOKSJHFFTTTEINSEINSDREIZWOYYQNNSNEUNINHALTXXBEIANGRIFFUNTERWASSERGEDRUECKTYWABOSXLETZTERGEGNERSTANDNULACHTDREINULUHRMARQUANTONJOTANEUNACHTSEYHSDREIYZWOZWONULGRADYACHTSMYSTOSSENACHXEKNSVIERMBFAELLTYNNNNNNOOOVIERYSICHTEINSNULL",
+		}, {
+			name: "m3 compatible",
+			rotors: []*RotorConfiguration{
+				{Name: RotorIII},
+				{Name: RotorII},
+				{Name: RotorI},
+				{Name: RotorBeta},
+			},
+			reflector: ReflectorBThin,
+			input:     "AAAAA",
+			expected:  "BDZGO",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewM4(tt.rotors, tt.reflector, tt.plugs)
+			assert.Nil(t, err)
+			res, err := e.Encode(tt.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res, "decrypted message should match")
+		})
+	}
+}
+
+func TestInvalidNewM4(t *testing.T) {
+	tests := []struct {
+		name      string
+		rotors    []string
+		reflector string
+	}{
+		{
+			name:      "three rotors",
+			rotors:    []string{RotorI, RotorII, RotorIII},
+			reflector: ReflectorBThin,
+		}, {
+			name:      "greek rotor in fast position",
+			rotors:    []string{RotorBeta, RotorII, RotorIII, RotorGamma},
+			reflector: ReflectorBThin,
+		}, {
+			name:      "no greek rotor",
+			rotors:    []string{RotorI, RotorII, RotorIII, RotorIV},
+			reflector: ReflectorBThin,
+		}, {
+			name:      "repeated rotor",
+			rotors:    []string{RotorI, RotorII, RotorI, RotorBeta},
+			reflector: ReflectorBThin,
+		}, {
+			name:      "thick reflector",
+			rotors:    []string{RotorI, RotorII, RotorIII, RotorBeta},
+			reflector: ReflectorB,
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := []*RotorConfiguration{}
+			for _, r := range tt.rotors {
+				conf = append(conf, &RotorConfiguration{Name: r})
+			}
+			e, err := NewM4(conf, tt.reflector, "")
+			assert.Nil(t, e)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewFromModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		model     string
+		rotors    []*RotorConfiguration
+		reflector string
+		plugs     string
+		opts      []Option
+		input     string
+		expected  string
+	}{
+		{
+			// Operation Barbarossa message of 7 July 1941, first part
+			name:  "barbarossa",
+			model: ModelM3,
+			rotors: []*RotorConfiguration{
+				{Name: RotorV, Position: 0, RingSetting: 11},
+				{Name: RotorIV, Position: 11, RingSetting: 20},
+				{Name: RotorII, Position: 1, RingSetting: 1},
+			},
+			reflector: ReflectorB,
+			plugs:     "AV BS CG DL FU HZ IN KM OW RX",
+			input:     "EDPUDNRGYSZRCXNUYTPOMRMBOFKTBZREZKMLXLVEFGUEYSIOZVEQMIKUBPMMYLKLTTDEISMDICAGYKUACTCDOMOHWXMUUIAUBSTSLRNBZSZWNRFXWFYSSXJZVIJHIDISHPRKLKAYUPADTXQSPINQMATLPIFSVKDASCTACDPBOPVHJK",
+			expected:  "AUFKLXABTEILUNGXVONXKURTINOWAXKURTINOWAXNORDWESTLXSEBEZXSEBEZXUAFFLIEGERSTRASZERIQTUNGXDUBROWKIXDUBROWKIXOPOTSCHKAXOPOTSCHKAXUMXEINSAQTDREINULLXUHRANGETRETENXANGRIFFXINFXRGTX",
+		}, {
+			name:  "enigma I",
+			model: ModelEnigmaI,
+			rotors: []*RotorConfiguration{
+				{Name: RotorIII},
+				{Name: RotorII},
+				{Name: RotorI},
+			},
+			reflector: ReflectorB,
+			input:     "AAAAA",
+			expected:  "BDZGO",
+		}, {
+			// Vectors of the commercial machines are from referenceMachine below, written from the Crypto Museum
+			// wiring tables and descriptions, as no published traffic with full keys is at hand
+			name:  "commercial D",
+			model: ModelD,
+			rotors: []*RotorConfiguration{
+				{Name: RotorDIII},
+				{Name: RotorDII},
+				{Name: RotorDI},
+			},
+			reflector: ReflectorCommercial,
+			input:     "AAAAA",
+			expected:  "HWQIO",
+		}, {
+			name:  "swiss K with reflector position",
+			model: ModelK,
+			rotors: []*RotorConfiguration{
+				{Name: RotorKIII},
+				{Name: RotorKII, Position: 3, RingSetting: 2},
+				{Name: RotorKI, Position: 5},
+			},
+			reflector: ReflectorCommercial,
+			opts:      []Option{WithReflectorPosition(7)},
+			input:     "HELLOWORLD",
+			expected:  "WQPEKODMFK",
+		}, {
+			name:  "tirpitz",
+			model: ModelT,
+			rotors: []*RotorConfiguration{
+				{Name: RotorTIII},
+				{Name: RotorTII, Position: 21},
+				{Name: RotorTI, Position: 3, RingSetting: 1},
+			},
+			reflector: ReflectorT,
+			opts:      []Option{WithReflectorPosition(2)},
+			input:     "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			expected:  "CYIHHSICIOOKFEBBHWKZZWGJIQYRRK",
+		}, {
+			// From referenceMachine, the reflector turning from E to J on the way
+			name:  "abwehr G",
+			model: ModelG,
+			rotors: []*RotorConfiguration{
+				{Name: RotorGIII, Position: 25, RingSetting: 5},
+				{Name: RotorGII, Position: 24},
+				{Name: RotorGI, Position: 1, RingSetting: 3},
+			},
+			reflector: ReflectorG,
+			opts:      []Option{WithReflectorPosition(4)},
+			input:     "ABWEHRNACHRICHTENDIENST",
+			expected:  "FVXOZIZXTXQCDEKCLJWWRMH",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewFromModel(tt.model, tt.rotors, tt.reflector, tt.plugs, tt.opts...)
+			assert.Nil(t, err)
+			res, err := e.Encode(tt.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res, "decrypted message should match")
+		})
+	}
+}
+
+func TestModelGMovingReflector(t *testing.T) {
+	conf := []*RotorConfiguration{
+		{Name: RotorGIII, Position: 25, RingSetting: 5},
+		{Name: RotorGII, Position: 24},
+		{Name: RotorGI, Position: 1, RingSetting: 3},
+	}
+	e, err := NewFromModel(ModelG, conf, ReflectorG, "", WithReflectorPosition(4))
+	assert.Nil(t, err)
+	ref := &referenceMachine{
+		entry:             referenceQWERTZ,
+		rotors:            []string{"DMTWSILRUYQNKFEJCAZBPGXOHV", "HQZGPJTMOBLNCIFDYAWVEUSRKX", "UQNTLSZFMREHDPXKIBVYGJCWOA"},
+		notches:           []string{"SUVWZABCEFGIKLOPQ", "STVYZACDFGHKMNQ", "UWXAEFHKMNR"},
+		reflector:         "RULQMZJSYGOCETKWDAHNBXPVIF",
+		rings:             []int{3, 0, 5},
+		positions:         []int{1, 24, 25},
+		reflectorPosition: 4,
+		cog:               true,
+	}
+	input := strings.Repeat("ABWEHRNACHRICHTENDIENST", 20)
+	res, err := e.Encode(input)
+	assert.Nil(t, err)
+	assert.Equal(t, ref.encode(input), res, "enciphered message should match the reference")
+	assert.Equal(t, ref.positions, []int{e.rotors[2].position, e.rotors[1].position, e.rotors[0].position}, "rotor positions should match")
+	assert.Equal(t, ref.reflectorPosition, e.reflector.position, "reflector should turn with the rotors")
+	assert.NotEqual(t, 4, e.reflector.position, "reflector should have turned")
+}
+
+func TestModelsMatchReference(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      string
+		rotors     []string
+		reflector  string
+		reference  referenceMachine
+		reflectors bool
+	}{
+		{
+			name:      "commercial D",
+			model:     ModelD,
+			rotors:    []string{RotorDI, RotorDII, RotorDIII},
+			reflector: ReflectorCommercial,
+			reference: referenceMachine{
+				entry:     referenceQWERTZ,
+				rotors:    []string{"LPGSZMHAEOQKVXRFYBUTNICJDW", "SLVGBTFXJQOHEWIRZYAMKPCNDU", "CJGDPSHKTURAWZXFMYNQOBVLIE"},
+				notches:   []string{"Y", "E", "N"},
+				reflector: "IMETCGFRAYSQBZXWLHKDVUPOJN",
+			},
+		}, {
+			name:      "swiss K",
+			model:     ModelK,
+			rotors:    []string{RotorKI, RotorKII, RotorKIII},
+			reflector: ReflectorCommercial,
+			reference: referenceMachine{
+				entry:     referenceQWERTZ,
+				rotors:    []string{"PEZUOHXSCVFMTBGLRINQJWAYDK", "ZOUESYDKFWPCIQXHMVBLGNJRAT", "EHRVXGAOBQUSIMZFLYNWKTPDJC"},
+				notches:   []string{"Y", "E", "N"},
+				reflector: "IMETCGFRAYSQBZXWLHKDVUPOJN",
+			},
+		}, {
+			name:      "tirpitz",
+			model:     ModelT,
+			rotors:    []string{RotorTI, RotorTII, RotorTIII},
+			reflector: ReflectorT,
+			reference: referenceMachine{
+				entry:     "KZROUQHYAIGBLWVSTDXFPNMCJE",
+				rotors:    []string{"KPTYUELOCVGRFQDANJMBSWHZXI", "UPHZLWEQMTDJXCAKSOIGVBYFNR", "QUDLYRFEKONVZAXWHMGPJBSICT"},
+				notches:   []string{"EKQWZ", "FLRWZ", "EKQWZ"},
+				reflector: "GEKPBTAUMOCNILJDXZYFHWVQSR",
+			},
+		}, {
+			name:      "abwehr G",
+			model:     ModelG,
+			rotors:    []string{RotorGI, RotorGII, RotorGIII},
+			reflector: ReflectorG,
+			reference: referenceMachine{
+				entry:     referenceQWERTZ,
+				rotors:    []string{"DMTWSILRUYQNKFEJCAZBPGXOHV", "HQZGPJTMOBLNCIFDYAWVEUSRKX", "UQNTLSZFMREHDPXKIBVYGJCWOA"},
+				notches:   []string{"SUVWZABCEFGIKLOPQ", "STVYZACDFGHKMNQ", "UWXAEFHKMNR"},
+				reflector: "RULQMZJSYGOCETKWDAHNBXPVIF",
+				cog:       true,
+			},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			random := rand.New(rand.NewSource(312))
+			for i := 0; i < 50; i++ {
+				ref := tt.reference
+				ref.rings = []int{random.Intn(26), random.Intn(26), random.Intn(26)}
+				ref.positions = []int{random.Intn(26), random.Intn(26), random.Intn(26)}
+				ref.reflectorPosition = random.Intn(26)
+				conf := []*RotorConfiguration{}
+				for j := 2; j >= 0; j-- {
+					conf = append(conf, &RotorConfiguration{Name: tt.rotors[j], Position: ref.positions[j], RingSetting: ref.rings[j]})
+				}
+				e, err := NewFromModel(tt.model, conf, tt.reflector, "", WithReflectorPosition(ref.reflectorPosition))
+				assert.Nil(t, err)
+				input := strings.Repeat("ENIGMA", 120)
+				res, err := e.Encode(input)
+				assert.Nil(t, err)
+				assert.Equal(t, ref.encode(input), res, "enciphered message should match the reference for %v %v %d", ref.rings, ref.positions, ref.reflectorPosition)
+			}
+		})
+	}
+}
+
+const referenceQWERTZ = "QWERTZUIOASDFGHJKPYXCVBNML"
+
+// referenceMachine is a deliberately plain simulator, sharing no code with the package, written from the wiring
+// tables and machine descriptions of the Crypto Museum. Rotors, ring settings and positions are from left to right,
+// the notches are the letters showing when the rotor to the left is carried. The lever machines double step the
+// middle rotor; the cog machine carries as an odometer, on into the reflector
+type referenceMachine struct {
+	entry             string
+	rotors            []string
+	notches           []string
+	reflector         string
+	rings             []int
+	positions         []int
+	reflectorPosition int
+	cog               bool
+}
+
+func (m *referenceMachine) encode(text string) string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	m.positions = append([]int(nil), m.positions...)
+	notched := func(i int) bool {
+		return strings.IndexByte(m.notches[i], alphabet[m.positions[i]]) >= 0
+	}
+	step := func(i int) {
+		m.positions[i] = (m.positions[i] + 1) % 26
+	}
+	out := []byte{}
+	for i := 0; i < len(text); i++ {
+		if m.cog {
+			j := 2
+			for ; j >= 0; j-- {
+				carry := notched(j)
+				step(j)
+				if !carry {
+					break
+				}
+			}
+			if j < 0 {
+				m.reflectorPosition = (m.reflectorPosition + 1) % 26
+			}
+		} else {
+			middle, left := notched(2), notched(1)
+			if left {
+				step(0)
+				step(1)
+			} else if middle {
+				step(1)
+			}
+			step(2)
+		}
+		c := strings.IndexByte(m.entry, text[i])
+		for j := 2; j >= 0; j-- {
+			shift := (m.positions[j] - m.rings[j] + 26) % 26
+			c = (strings.IndexByte(alphabet, m.rotors[j][(c+shift)%26]) - shift + 26) % 26
+		}
+		c = (strings.IndexByte(alphabet, m.reflector[(c+m.reflectorPosition)%26]) - m.reflectorPosition + 26) % 26
+		for j := 0; j < 3; j++ {
+			shift := (m.positions[j] - m.rings[j] + 26) % 26
+			c = (strings.IndexByte(m.rotors[j], alphabet[(c+shift)%26]) - shift + 26) % 26
+		}
+		out = append(out, m.entry[c])
+	}
+	return string(out)
+}
+
+func TestInvalidNewFromModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		model     string
+		rotors    []string
+		reflector string
+		plugs     string
+		opts      []Option
+	}{
+		{
+			name:      "unknown model",
+			model:     "EnigmaZ",
+			rotors:    []string{RotorI, RotorII, RotorIII},
+			reflector: ReflectorB,
+		}, {
+			name:      "rotor not issued",
+			model:     ModelEnigmaI,
+			rotors:    []string{RotorVI, RotorII, RotorIII},
+			reflector: ReflectorB,
+		}, {
+			name:      "reflector not issued",
+			model:     ModelM3,
+			rotors:    []string{RotorI, RotorII, RotorIII},
+			reflector: ReflectorA,
+		}, {
+			name:      "greek rotor without greek position",
+			model:     ModelM3,
+			rotors:    []string{RotorI, RotorII, RotorIII, RotorBeta},
+			reflector: ReflectorB,
+		}, {
+			name:      "plugboard on commercial",
+			model:     ModelD,
+			rotors:    []string{RotorDI, RotorDII, RotorDIII},
+			reflector: ReflectorCommercial,
+			plugs:     "AZ",
+		}, {
+			name:      "settable reflector on military",
+			model:     ModelM3,
+			rotors:    []string{RotorI, RotorII, RotorIII},
+			reflector: ReflectorB,
+			opts:      []Option{WithReflectorPosition(4)},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := []*RotorConfiguration{}
+			for _, r := range tt.rotors {
+				conf = append(conf, &RotorConfiguration{Name: r})
+			}
+			e, err := NewFromModel(tt.model, conf, tt.reflector, tt.plugs, tt.opts...)
+			assert.Nil(t, e)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestModelValidate(t *testing.T) {
+	m := &Model{
+		Name:       "training",
+		Rotors:     []string{RotorI, RotorII, RotorIII},
+		Reflectors: []string{ReflectorB},
+		RotorCount: 3,
+		Stepper:    LeverStepper{},
+	}
+	tests := []struct {
+		name    string
+		ring    int
+		plugs   string
+		isValid bool
+	}{
+		{
+			name:    "base",
+			isValid: true,
+		}, {
+			name:  "ring setting",
+			ring:  4,
+			plugs: "",
+		}, {
+			name:  "plugboard",
+			plugs: "AZ",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := []*RotorConfiguration{
+				{Name: RotorI, RingSetting: tt.ring},
+				{Name: RotorII},
+				{Name: RotorIII},
+			}
+			err := m.Validate(conf, ReflectorB, tt.plugs)
+			if tt.isValid {
+				assert.Nil(t, err)
+				return
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGetModelCopies(t *testing.T) {
+	m, err := GetModel(ModelEnigmaI)
+	assert.Nil(t, err)
+	m.Rotors = append(m.Rotors, "Bogus")
+	m.Reflectors[0] = "Bogus"
+	m.Stepper = CogStepper{}
+
+	m, err = GetModel(ModelEnigmaI)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{RotorI, RotorII, RotorIII, RotorIV, RotorV}, m.Rotors, "enigma I rotors should not be changed")
+	assert.Equal(t, ReflectorA, m.Reflectors[0], "enigma I reflectors should not be changed")
+	assert.Equal(t, LeverStepper{}, m.Stepper, "enigma I stepper should not be changed")
+	m3, err := GetModel(ModelM3)
+	assert.Nil(t, err)
+	assert.Equal(t, militaryRotors, m3.Rotors, "M3 rotors should not be changed")
+	assert.Equal(t, RotorVI, militaryRotors[5], "military rotors should not be changed")
+}
@@ -0,0 +1,61 @@
+package enigma
+
+// Observer receives the events of a machine as keys are pressed, such as to drive a visual simulator. Rotors are
+// counted from 0 for the fast rotor on the right, and the reflector is counted as the rotor after the last
+type Observer interface {
+	// KeyPressed is called with the upper case letter of each key press, before the rotors step
+	KeyPressed(r rune)
+	// NotchEngaged is called when the notch of a rotor steps its neighbour on the left, including the double step
+	NotchEngaged(rotor int)
+	// RotorStepped is called with the new position of each rotor that moves
+	RotorStepped(rotor, position int)
+	// LampLit is called with the letter lit on the lampboard
+	LampLit(r rune)
+}
+
+// NopObserver ignores all events, and can be embedded to observe only some of them
+type NopObserver struct{}
+
+// KeyPressed ignores the event
+func (NopObserver) KeyPressed(r rune) {}
+
+// NotchEngaged ignores the event
+func (NopObserver) NotchEngaged(rotor int) {}
+
+// RotorStepped ignores the event
+func (NopObserver) RotorStepped(rotor, position int) {}
+
+// LampLit ignores the event
+func (NopObserver) LampLit(r rune) {}
+
+// WithObserver registers an observer to receive the events of the machine. Clones of the machine share the observer
+func WithObserver(o Observer) Option {
+	return func(e *Enigma) error {
+		e.observer = o
+		return nil
+	}
+}
+
+// observeCycle steps the rotors as cycle does, reporting the notches engaged and the rotors stepped by comparing the
+// positions either side of stepping
+func (e *Enigma) observeCycle() {
+	before := e.Snapshot()
+	notched := make([]bool, len(e.rotors))
+	for i, r := range e.rotors {
+		notched[i] = r.isNotchEngaged()
+	}
+	e.stepper.Step(e.rotors, e.reflector)
+	after := e.Snapshot()
+	stepped := append(after.Positions, after.Reflector)
+	previous := append(before.Positions, before.Reflector)
+	for i := range e.rotors {
+		if notched[i] && stepped[i+1] != previous[i+1] {
+			e.observer.NotchEngaged(i)
+		}
+	}
+	for i := range stepped {
+		if stepped[i] != previous[i] {
+			e.observer.RotorStepped(i, stepped[i])
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package enigma
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) KeyPressed(r rune) {
+	o.events = append(o.events, fmt.Sprintf("key %c", r))
+}
+
+func (o *recordingObserver) NotchEngaged(rotor int) {
+	o.events = append(o.events, fmt.Sprintf("notch %d", rotor))
+}
+
+func (o *recordingObserver) RotorStepped(rotor, position int) {
+	o.events = append(o.events, fmt.Sprintf("step %d %d", rotor, position))
+}
+
+func (o *recordingObserver) LampLit(r rune) {
+	o.events = append(o.events, fmt.Sprintf("lamp %c", r))
+}
+
+type lampObserver struct {
+	NopObserver
+	lamps []rune
+}
+
+func (o *lampObserver) LampLit(r rune) {
+	o.lamps = append(o.lamps, r)
+}
+
+func TestObserver(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		model    string
+		opts     []Option
+		input    string
+		expected []string
+	}{
+		{
+			name:  "double step",
+			key:   "B I-II-III 01-01-01 ADU",
+			input: "AAa",
+			expected: []string{
+				"key A", "step 0 21", "lamp E",
+				"key A", "notch 0", "step 0 22", "step 1 4", "lamp Q",
+				"key A", "notch 1", "step 0 23", "step 1 5", "step 2 1", "lamp I",
+			},
+		}, {
+			name:  "retained characters",
+			key:   "B I-II-III 01-01-01 ADU",
+			input: "A 1",
+			expected: []string{
+				"key A", "step 0 21", "lamp E",
+			},
+		}, {
+			name:  "reflector",
+			key:   "B I-II-III 01-01-01 QEV",
+			opts:  []Option{WithStepper(CogStepper{})},
+			input: "A",
+			expected: []string{
+				"key A", "notch 0", "notch 1", "notch 2", "step 0 22", "step 1 5", "step 2 17", "step 3 1", "lamp X",
+			},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			o := &recordingObserver{}
+			k, err := ParseKey(tt.key)
+			assert.Nil(t, err)
+			e, err := k.Enigma(append(tt.opts, WithObserver(o))...)
+			assert.Nil(t, err)
+			_, err = e.Encode(tt.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, o.events, "events should match")
+		})
+	}
+}
+
+func TestNopObserver(t *testing.T) {
+	o := &lampObserver{}
+	k, err := ParseKey("B I-II-III 01-01-01 ADU")
+	assert.Nil(t, err)
+	e, err := k.Enigma(WithObserver(o))
+	assert.Nil(t, err)
+	res, err := e.Encode("AAAAA")
+	assert.Nil(t, err)
+	assert.Equal(t, "EQIBM", res, "encoded string should match")
+	assert.Equal(t, []rune(res), o.lamps, "lamps should match the output")
+}
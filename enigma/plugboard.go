@@ -8,62 +8,71 @@ import (
 // Plugboard is the internal representation of the enigma plugboard
 type Plugboard struct {
 	connections map[int]int
+	table       [26]uint8
 }
 
 // newPlugboard takes int pair configurations and converts them with validation to a plugboard object
 func newPlugboard(pairs [][]int) (*Plugboard, error) {
 	p := Plugboard{connections: make(map[int]int)}
 	if len(pairs) > 10 {
-		return nil, fmt.Errorf("Too many plugs, limit is 10: %v", len(pairs))
+		return nil, &PlugboardError{Reason: fmt.Errorf("%w, limit is 10: %d", ErrTooManyPairs, len(pairs))}
 	}
 	for _, pair := range pairs {
 		if len(pair) != 2 {
-			return nil, fmt.Errorf("Too many entries in plugboard pair: %v", pair)
+			return nil, &PlugboardError{Pair: pairString(pair), Reason: ErrMalformedPair}
 		}
 		if pair[0] == pair[1] {
-			return nil, fmt.Errorf("Cannot pair character with self: %v", pair)
+			return nil, &PlugboardError{Pair: pairString(pair), Reason: ErrSelfConnection}
 		}
 		if pair[0] < 0 || pair[0] > 25 || pair[1] < 0 || pair[1] > 25 {
-			return nil, fmt.Errorf("Invalid characters: %v", pair)
+			return nil, &PlugboardError{Pair: pairString(pair), Reason: ErrInvalidCharacter}
 		}
 		_, ok1 := p.connections[pair[0]]
 		_, ok2 := p.connections[pair[1]]
 		if ok1 || ok2 {
-			return nil, fmt.Errorf("Attempted to pair character again: %v", pair)
+			return nil, &PlugboardError{Pair: pairString(pair), Reason: ErrRepeatedLetter}
 		}
 		p.connections[pair[0]] = pair[1]
 		p.connections[pair[1]] = pair[0]
 	}
+	for i := range p.table {
+		p.table[i] = uint8(p.traverse(i))
+	}
 	return &p, nil
 }
 
 // parseStringPlugboard converts a string representation of a plugboard to a set of int pairs
 func parseStringPlugboard(s string) ([][]int, error) {
+	return parseStringPairs(s, 10)
+}
+
+// parseStringPairs converts a string of space separated letter pairs to a set of int pairs, allowing up to limit pairs
+func parseStringPairs(s string, limit int) ([][]int, error) {
 	if s == "" {
 		return nil, nil
 	}
 	pairs := strings.Split(s, " ")
-	if len(pairs) > 10 {
-		return nil, fmt.Errorf("too many plugboard pairs: %d", len(pairs))
+	if len(pairs) > limit {
+		return nil, &PlugboardError{Reason: fmt.Errorf("%w, limit is %d: %d", ErrTooManyPairs, limit, len(pairs))}
 	}
 	res := [][]int{}
 	occupied := map[rune]bool{}
 	for _, p := range pairs {
 		if len(p) != 2 {
-			return nil, fmt.Errorf("invalid plugboard configuration %s, can only connect two letters", p)
+			return nil, &PlugboardError{Pair: p, Reason: ErrMalformedPair}
 		}
 		if p[0] == p[1] {
-			return nil, fmt.Errorf("invalid plugboard connection %s, cannot connect letter to self", p)
+			return nil, &PlugboardError{Pair: p, Reason: ErrSelfConnection}
 		}
 		r1 := rune(p[0])
 		r2 := rune(p[1])
 		if !isAllowedCharacter(r1) || !isAllowedCharacter(r2) {
-			return nil, fmt.Errorf("invalid characters %s, must be upper case [A-Z]", p)
+			return nil, &PlugboardError{Pair: p, Reason: ErrInvalidCharacter}
 		}
 		_, ok0 := occupied[r1]
 		_, ok1 := occupied[r2]
 		if ok0 || ok1 {
-			return nil, fmt.Errorf("invalid plugboard configuration %s, repeated letter", p)
+			return nil, &PlugboardError{Pair: p, Reason: ErrRepeatedLetter}
 		}
 		occupied[r1] = true
 		occupied[r2] = true
@@ -75,9 +84,20 @@ func parseStringPlugboard(s string) ([][]int, error) {
 
 func (p *Plugboard) traverse(input int) int {
 	out, ok := p.connections[input]
-	fmt.Println(input, out, ok, p.connections)
 	if ok {
 		return out
 	}
 	return input
 }
+
+// String returns the plugboard as space separated letter pairs, ordered alphabetically
+func (p *Plugboard) String() string {
+	pairs := []string{}
+	for i := 0; i < 26; i++ {
+		j, ok := p.connections[i]
+		if ok && j > i {
+			pairs = append(pairs, string([]rune{rune(i) + runeOffset, rune(j) + runeOffset}))
+		}
+	}
+	return strings.Join(pairs, " ")
+}
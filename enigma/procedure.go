@@ -0,0 +1,65 @@
+package enigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EncryptDoubledKey enciphers a message with the procedure used until 1940. The machine is set up with the daily
+// key and the message key chosen by the operator, one letter per rotor, is enciphered twice from the Grundstellung,
+// the positions of the daily key, giving the indicator. The body is then enciphered from the message key
+func EncryptDoubledKey(daily *Key, messageKey, text string, opts ...Option) (indicator, body string, err error) {
+	e, err := daily.Enigma(opts...)
+	if err != nil {
+		return "", "", err
+	}
+	messageKey, err = parseLetters(ErrInvalidKey, "message key", messageKey, len(daily.Rotors))
+	if err != nil {
+		return "", "", err
+	}
+	indicator, err = e.Encode(messageKey + messageKey)
+	if err != nil {
+		return "", "", err
+	}
+	body, err = e.EncryptMessage(messageKey, text)
+	if err != nil {
+		return "", "", err
+	}
+	return indicator, body, nil
+}
+
+// DecryptDoubledKey deciphers a message sent with the procedure used until 1940, recovering the message key from
+// the indicator at the Grundstellung of the daily key. The indicator must decipher to the same key twice
+func DecryptDoubledKey(daily *Key, indicator, body string, opts ...Option) (messageKey, text string, err error) {
+	e, err := daily.Enigma(opts...)
+	if err != nil {
+		return "", "", err
+	}
+	indicator = Ungroup(indicator)
+	if len(indicator) != 2*len(daily.Rotors) {
+		return "", "", fmt.Errorf("%w: %q must be %d letters", ErrInvalidIndicator, indicator, 2*len(daily.Rotors))
+	}
+	doubled, err := e.Encode(indicator)
+	if err != nil {
+		return "", "", err
+	}
+	messageKey = doubled[:len(daily.Rotors)]
+	if doubled[len(daily.Rotors):] != messageKey {
+		return "", "", fmt.Errorf("%w: %s deciphers to %s, which is not a doubled key", ErrInvalidIndicator, indicator, doubled)
+	}
+	text, err = e.DecryptMessage(messageKey, body)
+	if err != nil {
+		return "", "", err
+	}
+	return messageKey, text, nil
+}
+
+// parseLetters checks a setting or group of a procedure is length letters, returning it upper cased, or else an
+// error wrapping the given sentinel
+func parseLetters(sentinel error, name, s string, length int) (string, error) {
+	u := strings.ToUpper(s)
+	if len(u) != length || strings.IndexFunc(u, func(r rune) bool { return !isAllowedCharacter(r) }) >= 0 {
+		return "", fmt.Errorf("%w: %s %q must be %d letters", sentinel, name, s, length)
+	}
+	return u, nil
+}
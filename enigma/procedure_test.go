@@ -0,0 +1,86 @@
+package enigma
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoubledKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		daily      string
+		messageKey string
+		text       string
+		opts       []Option
+		indicator  string
+		body       string
+	}{
+		{
+			name:       "base",
+			daily:      "B I-II-III 01-01-01 AAA",
+			messageKey: "AAA",
+			text:       "AAAAA",
+			indicator:  "BDZGOW",
+		}, {
+			name:       "plugboard",
+			daily:      "B II-IV-V 02-21-12 WXC AV BS CG DL FU HZ IN KM OW RX",
+			messageKey: "bla",
+			text:       "AUFKLXABTEILUNGXVONXKURTINOWA",
+			// Operation Barbarossa message of 7 July 1941, first part, sent with the later procedure to the same key
+			body: "EDPUDNRGYSZRCXNUYTPOMRMBOFKTB",
+		}, {
+			name:       "conventions",
+			daily:      "C V-I-III 14-09-24 XQR AE BF CM DQ HU JN LX PR SZ VW",
+			messageKey: "RTZ",
+			text:       "Angriff um 1830 Uhr.",
+			opts:       []Option{WithConventions(ConventionsArmy)},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			daily, err := ParseKey(tt.daily)
+			assert.Nil(t, err)
+			indicator, body, err := EncryptDoubledKey(daily, tt.messageKey, tt.text, tt.opts...)
+			assert.Nil(t, err)
+			assert.Len(t, indicator, 6)
+			if tt.indicator != "" {
+				assert.Equal(t, tt.indicator, indicator, "indicator should match")
+			}
+
+			e, err := daily.Enigma(tt.opts...)
+			assert.Nil(t, err)
+			if tt.body == "" {
+				tt.body, err = e.EncryptMessage(strings.ToUpper(tt.messageKey), tt.text)
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.body, body, "body should be enciphered from the message key")
+
+			messageKey, text, err := DecryptDoubledKey(daily, indicator, body, tt.opts...)
+			assert.Nil(t, err)
+			assert.Equal(t, strings.ToUpper(tt.messageKey), messageKey, "message key should be recovered")
+			plain, err := e.DecryptMessage(messageKey, body)
+			assert.Nil(t, err)
+			assert.Equal(t, plain, text, "text should match")
+		})
+	}
+}
+
+func TestInvalidDoubledKey(t *testing.T) {
+	daily, err := ParseKey("B I-II-III 01-01-01 AAA")
+	assert.Nil(t, err)
+
+	_, _, err = EncryptDoubledKey(daily, "AB", "AAAAA")
+	assert.True(t, errors.Is(err, ErrInvalidKey), "%v should be invalid key", err)
+	_, _, err = EncryptDoubledKey(daily, "A1B", "AAAAA")
+	assert.True(t, errors.Is(err, ErrInvalidKey), "%v should be invalid key", err)
+
+	_, _, err = DecryptDoubledKey(daily, "BDZGOX", "AAAAA")
+	assert.True(t, errors.Is(err, ErrInvalidIndicator), "%v should be invalid indicator", err)
+	_, _, err = DecryptDoubledKey(daily, "BDZGO", "AAAAA")
+	assert.True(t, errors.Is(err, ErrInvalidIndicator), "%v should be invalid indicator", err)
+}
@@ -0,0 +1,65 @@
+package enigma
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ReflectorD is the name of the rewirable reflector, Umkehrwalze D, wired by the operator with NewReflectorD
+const ReflectorD = "ReflectorD"
+
+// Notations Names of the letterings used to write the pairs wired into the UKW-D
+const NotationBletchley = "Bletchley"
+const NotationGerman = "German"
+
+// errJYFixed is the reason a UKW-D pair including J or Y is rejected
+var errJYFixed = errors.New("J and Y are a fixed pair")
+
+// The sockets of the UKW-D were lettered in reverse order to the rotor contacts. Each German letter maps to the
+// Bletchley letter in the same position; J and Y form the fixed pair in both notations and carry no socket
+const germanSockets = "ABCDEFGHIKLMNOPQRSTUVWXZ"
+const bletchleySockets = "AZXWVUTSRQPONMLKIHGFEDCB"
+
+// NewReflectorD builds a rewired UKW-D from the 12 plug pairs, given in Bletchley or German notation. The fixed J-Y
+// pair may be omitted or given as a 13th pair
+func NewReflectorD(pairs string, notation string) (*Rotor, error) {
+	if notation != NotationBletchley && notation != NotationGerman {
+		return nil, &UnknownComponentError{Kind: "notation", Name: notation}
+	}
+	plugs := []string{}
+	for _, p := range strings.Split(pairs, " ") {
+		if p == "JY" || p == "YJ" {
+			continue
+		}
+		if strings.ContainsAny(p, "JY") {
+			return nil, &PlugboardError{Pair: p, Reason: errJYFixed}
+		}
+		plugs = append(plugs, p)
+	}
+	if len(plugs) != 12 {
+		return nil, &PlugboardError{Reason: fmt.Errorf("the UKW-D requires 12 pairs: %d", len(plugs))}
+	}
+	parsed, err := parseStringPairs(strings.Join(plugs, " "), 12)
+	if err != nil {
+		return nil, err
+	}
+	wiring := []rune(entryWheelABC)
+	wiring['J'-runeOffset], wiring['Y'-runeOffset] = 'Y', 'J'
+	for _, pair := range parsed {
+		a, b := pair[0], pair[1]
+		if notation == NotationGerman {
+			a, b = toBletchley(a), toBletchley(b)
+		}
+		wiring[a], wiring[b] = rune(b)+runeOffset, rune(a)+runeOffset
+	}
+	return newRotor(&RotorConfiguration{
+		Name:          ReflectorD,
+		Configuration: string(wiring),
+	})
+}
+
+// toBletchley converts a letter of the German notation to that of the Bletchley notation
+func toBletchley(i int) int {
+	return int(bletchleySockets[strings.IndexRune(germanSockets, rune(i)+runeOffset)]) - runeOffset
+}
@@ -0,0 +1,134 @@
+package enigma
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReflectorD(t *testing.T) {
+	tests := []struct {
+		name     string
+		pairs    string
+		notation string
+		expected string
+	}{
+		{
+			name:     "bletchley",
+			pairs:    "AZ XW VU TS RQ PO NM LK IH GF ED CB",
+			notation: NotationBletchley,
+			expected: "AZ XW VU TS RQ PO NM LK IH GF ED CB",
+		}, {
+			name:     "german",
+			pairs:    "AB CD EF GH IK LM NO PQ RS TU VW XZ",
+			notation: NotationGerman,
+			expected: "AZ XW VU TS RQ PO NM LK IH GF ED CB",
+		}, {
+			name:     "fixed pair given",
+			pairs:    "AZ XW VU TS RQ PO NM LK IH GF ED CB JY",
+			notation: NotationBletchley,
+			expected: "AZ XW VU TS RQ PO NM LK IH GF ED CB",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := NewReflectorD(tt.pairs, tt.notation)
+			assert.Nil(t, err)
+			expected, err := NewReflectorD(tt.expected, NotationBletchley)
+			assert.Nil(t, err)
+			assert.Equal(t, ReflectorD, r.Name, "reflector name should match")
+			assert.Equal(t, expected.connections, r.connections, "connections should match")
+			assert.Equal(t, 24, r.traverse(9, true), "J should be wired to Y")
+			for i := 0; i < 26; i++ {
+				assert.NotEqual(t, i, r.traverse(i, true), "no letter should be wired to itself")
+				assert.Equal(t, i, r.traverse(r.traverse(i, true), true), "wiring should be reciprocal")
+			}
+		})
+	}
+}
+
+func TestInvalidNewReflectorD(t *testing.T) {
+	tests := []struct {
+		name     string
+		pairs    string
+		notation string
+	}{
+		{
+			name:     "too few",
+			pairs:    "AZ XW VU TS RQ PO NM LK IH GF ED",
+			notation: NotationBletchley,
+		}, {
+			name:     "fixed letter",
+			pairs:    "AZ XW VU TS RQ PO NM LK IH GF ED CJ",
+			notation: NotationBletchley,
+		}, {
+			name:     "repeated",
+			pairs:    "AZ XW VU TS RQ PO NM LK IH GF ED CA",
+			notation: NotationBletchley,
+		}, {
+			name:     "invalid characters",
+			pairs:    "az XW VU TS RQ PO NM LK IH GF ED CB",
+			notation: NotationBletchley,
+		}, {
+			name:     "unknown notation",
+			pairs:    "AZ XW VU TS RQ PO NM LK IH GF ED CB",
+			notation: "Polish",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := NewReflectorD(tt.pairs, tt.notation)
+			assert.Nil(t, r)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEncodeReflectorD(t *testing.T) {
+	r, err := NewReflectorD("AB CD EF GH IK LM NO PQ RS TU VW XZ", NotationGerman)
+	assert.Nil(t, err)
+	conf := []*RotorConfiguration{
+		{Name: RotorIII},
+		{Name: RotorII},
+		{Name: RotorI},
+	}
+	e, err := NewFromModel(ModelEnigmaI, conf, "", "AZ", WithReflector(r))
+	assert.Nil(t, err)
+	cipher, err := e.Encode("LUFTWAFFE")
+	assert.Nil(t, err)
+	assert.NotEqual(t, "LUFTWAFFE", cipher)
+	for _, r := range e.rotors {
+		r.position = 0
+	}
+	res, err := e.Encode(cipher)
+	assert.Nil(t, err)
+	assert.Equal(t, "LUFTWAFFE", res, "decoded string should match")
+
+	e, err = NewFromModel(ModelM3, conf, "", "", WithReflector(r))
+	assert.Nil(t, e)
+	assert.Error(t, err)
+}
+
+func TestWithReflectorCopies(t *testing.T) {
+	r, err := NewReflectorD("AB CD EF GH IK LM NO PQ RS TU VW XZ", NotationGerman)
+	assert.Nil(t, err)
+	conf := []*RotorConfiguration{
+		{Name: RotorGIII},
+		{Name: RotorGII},
+		{Name: RotorGI},
+	}
+	e1, err := New(conf, "", "", WithReflector(r), WithStepper(CogStepper{}), WithReflectorPosition(3))
+	assert.Nil(t, err)
+	e2, err := New(conf, "", "", WithReflector(r))
+	assert.Nil(t, err)
+	_, err = e1.Encode(strings.Repeat("A", 26*26*26))
+	assert.Nil(t, err)
+	assert.NotEqual(t, 3, e1.reflector.position, "cog stepping should turn the reflector")
+	assert.Equal(t, 0, e2.reflector.position, "reflector should not be shared between machines")
+	assert.Equal(t, 0, r.position, "reflector given should not be changed")
+}
@@ -0,0 +1,92 @@
+package enigma
+
+import (
+	"fmt"
+)
+
+// RegisterRotor adds a rotor with a custom wiring to those available by name. The wiring lists the letters [A-Z]
+// map to in position 0 and must be a permutation, notches lists the letters at which the next rotor is stepped
+func RegisterRotor(name, wiring, notches string) error {
+	err := validateWiring(wiring)
+	if err != nil {
+		return fmt.Errorf("unable to register rotor %s: %w", name, err)
+	}
+	n, err := parseNotches(notches)
+	if err != nil {
+		return fmt.Errorf("unable to register rotor %s: %w", name, err)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" {
+		return fmt.Errorf("unable to register rotor: name must not be empty")
+	}
+	if _, ok := rotorWirings[name]; ok {
+		return fmt.Errorf("unable to register rotor %s: name already registered", name)
+	}
+	rotorWirings[name] = wiring
+	rotorNotches[name] = n
+	return nil
+}
+
+// RegisterReflector adds a reflector with a custom wiring to those available by name. The wiring must be a
+// permutation that pairs letters with each other, with no letter wired to itself
+func RegisterReflector(name, wiring string) error {
+	err := validateWiring(wiring)
+	if err != nil {
+		return fmt.Errorf("unable to register reflector %s: %w", name, err)
+	}
+	for i, r := range wiring {
+		j := int(r - runeOffset)
+		if i == j {
+			return fmt.Errorf("unable to register reflector %s: %w: %c is wired to itself", name, ErrInvalidWiring, r)
+		}
+		if int(wiring[j]-runeOffset) != i {
+			return fmt.Errorf("unable to register reflector %s: %w: %c and %c are not wired to each other", name, ErrInvalidWiring, rune(i)+runeOffset, r)
+		}
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if name == "" {
+		return fmt.Errorf("unable to register reflector: name must not be empty")
+	}
+	if _, ok := reflectorWirings[name]; ok {
+		return fmt.Errorf("unable to register reflector %s: name already registered", name)
+	}
+	reflectorWirings[name] = wiring
+	return nil
+}
+
+// validateWiring checks a wiring is a permutation of the letters [A-Z]
+func validateWiring(wiring string) error {
+	if len(wiring) != 26 {
+		return fmt.Errorf("%w: must have 26 letters: %d", ErrInvalidWiring, len(wiring))
+	}
+	seen := map[rune]bool{}
+	for _, r := range wiring {
+		if !isAllowedCharacter(r) {
+			return fmt.Errorf("%w: invalid character %c, must be upper case [A-Z]", ErrInvalidWiring, r)
+		}
+		if seen[r] {
+			return fmt.Errorf("%w: repeated character %c", ErrInvalidWiring, r)
+		}
+		seen[r] = true
+	}
+	return nil
+}
+
+// parseNotches converts a string of notch letters to notch positions
+func parseNotches(notches string) ([]int, error) {
+	res := []int{}
+	seen := map[rune]bool{}
+	for _, r := range notches {
+		if !isAllowedCharacter(r) {
+			return nil, fmt.Errorf("%w: invalid notch %c, must be upper case [A-Z]", ErrInvalidPosition, r)
+		}
+		if seen[r] {
+			return nil, fmt.Errorf("%w: repeated notch %c", ErrInvalidPosition, r)
+		}
+		seen[r] = true
+		res = append(res, int(r-runeOffset))
+	}
+	return res, nil
+}
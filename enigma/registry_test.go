@@ -0,0 +1,101 @@
+package enigma
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterRotor(t *testing.T) {
+	err := RegisterRotor("TestRotorShift", "BCDEFGHIJKLMNOPQRSTUVWXYZA", "AN")
+	assert.Nil(t, err)
+	c, err := NewRotorConfiguration("TestRotorShift", 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "BCDEFGHIJKLMNOPQRSTUVWXYZA", c.Configuration, "wiring should match")
+	assert.Equal(t, []int{0, 13}, c.Notches, "notches should match")
+
+	err = RegisterReflector("TestReflectorSwap", "BADCFEHGJILKNMPORQTSVUXWZY")
+	assert.Nil(t, err)
+	e, err := New([]*RotorConfiguration{c, {Name: RotorII}, {Name: RotorI}}, "TestReflectorSwap", "")
+	assert.Nil(t, err)
+	res, err := e.Encode("AAAAA")
+	assert.Nil(t, err)
+	assert.Len(t, res, 5)
+}
+
+func TestInvalidRegisterRotor(t *testing.T) {
+	tests := []struct {
+		name    string
+		rotor   string
+		wiring  string
+		notches string
+	}{
+		{
+			name:   "existing",
+			rotor:  RotorI,
+			wiring: "BCDEFGHIJKLMNOPQRSTUVWXYZA",
+		}, {
+			name:   "empty name",
+			wiring: "BCDEFGHIJKLMNOPQRSTUVWXYZA",
+		}, {
+			name:   "short",
+			rotor:  "TestRotorShort",
+			wiring: "BCDEFGHIJKLMNOPQRSTUVWXYZ",
+		}, {
+			name:   "not a permutation",
+			rotor:  "TestRotorRepeat",
+			wiring: "BCDEFGHIJKLMNOPQRSTUVWXYZB",
+		}, {
+			name:   "invalid characters",
+			rotor:  "TestRotorLower",
+			wiring: "bcdefghijklmnopqrstuvwxyza",
+		}, {
+			name:    "invalid notch",
+			rotor:   "TestRotorNotch",
+			wiring:  "BCDEFGHIJKLMNOPQRSTUVWXYZA",
+			notches: "a",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := RegisterRotor(tt.rotor, tt.wiring, tt.notches)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestInvalidRegisterReflector(t *testing.T) {
+	tests := []struct {
+		name      string
+		reflector string
+		wiring    string
+	}{
+		{
+			name:      "existing",
+			reflector: ReflectorB,
+			wiring:    "BADCFEHGJILKNMPORQTSVUXWZY",
+		}, {
+			name:      "not a permutation",
+			reflector: "TestReflectorRepeat",
+			wiring:    "BADCFEHGJILKNMPORQTSVUXWZB",
+		}, {
+			name:      "fixed point",
+			reflector: "TestReflectorFixed",
+			wiring:    "ABDCFEHGJILKNMPORQTSVUXWZY",
+		}, {
+			name:      "not an involution",
+			reflector: "TestReflectorShift",
+			wiring:    "BCDEFGHIJKLMNOPQRSTUVWXYZA",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := RegisterReflector(tt.reflector, tt.wiring)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,29 +2,34 @@ package enigma
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 )
 
 // Rotor is the object represting an active rotor, including all connections and current state
 type Rotor struct {
 	Name        string
 	connections *[2][26]int
+	tables      *[2][26][26]uint8
 	position    int
 	ringSetting int
 	notches     map[int]bool
 }
 
-// RotorConfiguration is a description of a Rotor, with name, connection configuration and current state
+// RotorConfiguration is a description of a Rotor, with name, connection configuration and current state. Position
+// and RingSetting count from 0 for A. Configuration and Notches may be left empty to use those of the named rotor
 type RotorConfiguration struct {
-	name          string
-	configuration string
-	position      int
-	ringSetting   int
-	notches       []int
+	Name          string `json:"name" yaml:"name"`
+	Configuration string `json:"configuration,omitempty" yaml:"configuration,omitempty"`
+	Position      int    `json:"position" yaml:"position"`
+	RingSetting   int    `json:"ringSetting" yaml:"ringSetting"`
+	Notches       []int  `json:"notches,omitempty" yaml:"notches,omitempty"`
 }
 
 const runeOffset = 65 // A
 
-// NewRotorConfiguration takes the minimum required input to generate the required rotor configurations
+// NewRotorConfiguration takes the minimum required input to generate the required rotor configurations, with the
+// position and ring setting counting from 0 for A
 func NewRotorConfiguration(name string, position, setting int) (*RotorConfiguration, error) {
 	c, err := getRotor(name)
 	if err != nil {
@@ -34,54 +39,111 @@ func NewRotorConfiguration(name string, position, setting int) (*RotorConfigurat
 	if err != nil {
 		return nil, err
 	}
+	if err := checkPosition("rotor "+name, "start position", position); err != nil {
+		return nil, err
+	}
+	if err := checkPosition("rotor "+name, "ring setting", setting); err != nil {
+		return nil, err
+	}
 	return &RotorConfiguration{
-		name:          name,
-		configuration: c,
-		position:      position,
-		ringSetting:   setting,
-		notches:       n,
+		Name:          name,
+		Configuration: c,
+		Position:      position,
+		RingSetting:   setting,
+		Notches:       n,
 	}, nil
 }
 
-// NewRotorConfiguration takes the minimum required input to generate the required rotor configurations
-func fillRotorConfiguration(r *RotorConfiguration) error {
-	var err error
-	r.configuration, err = getRotor(r.name)
+// ParseRotorConfiguration generates a rotor configuration with the position and ring setting given as the operator
+// would, either as a letter "A" to "Z" or as a number "1" to "26"
+func ParseRotorConfiguration(name string, position, setting string) (*RotorConfiguration, error) {
+	p, err := ParseSetting(position)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid start position on rotor %v: %w", name, err)
 	}
-	r.notches, err = getNotches(r.name)
+	s, err := ParseSetting(setting)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid ring setting on rotor %v: %w", name, err)
 	}
-	return nil
+	return NewRotorConfiguration(name, p, s)
 }
 
-// NewRotor takes a configuration string of 26 characters and instantiates a rotor object
+// ParseSetting converts a rotor position or ring setting given as a letter "A" to "Z", or as a number "1" to "26"
+// as printed on the rings of later machines, to the position counting from 0 for A
+func ParseSetting(s string) (int, error) {
+	if len(s) == 1 && isAllowedCharacter(rune(s[0])) {
+		return int(rune(s[0]) - runeOffset), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 26 {
+		return 0, fmt.Errorf("%w: setting %q must be [A-Z] or [1-26]", ErrInvalidPosition, s)
+	}
+	return n - 1, nil
+}
+
+// fillRotorConfiguration returns a copy of the configuration, with the connection configuration and notches of the
+// named rotor where the caller left them empty
+func fillRotorConfiguration(r *RotorConfiguration) (*RotorConfiguration, error) {
+	if r == nil {
+		return nil, ErrMissingRotor
+	}
+	c := *r
+	if c.Configuration == "" {
+		var err error
+		c.Configuration, err = getRotor(c.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.Notches == nil {
+		// a custom wiring may be given under a new name, with no notches to fill in
+		if n, err := getNotches(c.Name); err == nil {
+			c.Notches = n
+		}
+	}
+	return &c, nil
+}
+
+// NewRotor takes a configuration string of 26 characters and instantiates a rotor object, validating the wiring
 func newRotor(r *RotorConfiguration) (*Rotor, error) {
-	connections, err := convertStringConfiguration(r.configuration, r.ringSetting)
+	if err := checkPosition("rotor "+r.Name, "ring setting", r.RingSetting); err != nil {
+		return nil, err
+	}
+	if err := validateWiring(r.Configuration); err != nil {
+		return nil, fmt.Errorf("unable to parse rotor configuration: %w", err)
+	}
+	connections, err := convertStringConfiguration(r.Configuration, r.RingSetting)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to parse rotor configuration: %v", err)
+		return nil, fmt.Errorf("unable to parse rotor configuration: %w", err)
 	}
-	if r.position < 0 || r.position >= 26 {
-		return nil, fmt.Errorf("Invalid start position %d on rotor %v", r.position, r.name)
+	if err := checkPosition("rotor "+r.Name, "start position", r.Position); err != nil {
+		return nil, err
 	}
 	notch := map[int]bool{}
-	for _, n := range r.notches {
-		if n < 0 || n >= 26 {
-			return nil, fmt.Errorf("Invalid notch position %d on rotor %v", n, r.name)
+	for _, n := range r.Notches {
+		if err := checkPosition("rotor "+r.Name, "notch position", n); err != nil {
+			return nil, err
 		}
 		notch[n] = true
 	}
 	return &Rotor{
-		Name:        r.name,
+		Name:        r.Name,
 		connections: connections,
-		position:    r.position,
-		ringSetting: r.ringSetting,
+		tables:      positionTables(connections),
+		position:    r.Position,
+		ringSetting: r.RingSetting,
 		notches:     notch,
 	}, nil
 }
 
+// checkPosition validates a position of a wheel, counting from 0 for A, reporting the component and setting at fault
+func checkPosition(component, setting string, position int) error {
+	if position < 0 || position >= 26 {
+		return &InvalidPositionError{Component: component, Setting: setting, Position: position}
+	}
+	return nil
+}
+
 // Traverse passes a signal through the rotor configuration, either forwards or backwards
 func (r *Rotor) traverse(position int, forwards bool) int {
 	offsetPosition := position + r.position
@@ -112,15 +174,42 @@ func (r *Rotor) cycle() {
 	r.position = (r.position + 1) % 26
 }
 
-// State returns a subset of information on the rotors
+// Step rotates the rotor by one position, for use by custom stepping mechanisms
+func (r *Rotor) Step() {
+	r.cycle()
+}
+
+// AtNotch reports whether a notch of the rotor is in the stepping position, for use by custom stepping mechanisms
+func (r *Rotor) AtNotch() bool {
+	return r.isNotchEngaged()
+}
+
+// State returns the configuration of the rotor in its current position, from which it can be instantiated again
 func (r *Rotor) State() *RotorConfiguration {
+	notches := []int{}
+	for n := range r.notches {
+		notches = append(notches, n)
+	}
+	sort.Ints(notches)
 	return &RotorConfiguration{
-		name:        r.Name,
-		position:    r.position,
-		ringSetting: r.ringSetting,
+		Name:          r.Name,
+		Configuration: r.configuration(),
+		Position:      r.position,
+		RingSetting:   r.ringSetting,
+		Notches:       notches,
 	}
 }
 
+// configuration recovers the configuration string of the rotor in position 0, removing the ring setting applied by
+// convertStringConfiguration
+func (r *Rotor) configuration() string {
+	conf := make([]rune, 26)
+	for i := range conf {
+		conf[i] = rune((r.connections[0][(i+r.ringSetting)%26]-r.ringSetting+26)%26) + runeOffset
+	}
+	return string(conf)
+}
+
 // convertStringConfiguration converts a single string of characters, representing what characters [A-Z] map to
 // in position 0, and returns a slice of the wire pairs
 func convertStringConfiguration(conf string, ringSetting int) (*[2][26]int, error) {
@@ -129,7 +218,7 @@ func convertStringConfiguration(conf string, ringSetting int) (*[2][26]int, erro
 	runes := []rune(conf)
 	for i, r := range runes {
 		if !isAllowedCharacter(r) {
-			return nil, fmt.Errorf("Forbidden Character in configuration: %b", conf[i])
+			return nil, fmt.Errorf("%w: forbidden character in configuration: %q", ErrInvalidWiring, r)
 		}
 		j := i + ringSetting
 		if j > 25 {
@@ -141,7 +230,7 @@ func convertStringConfiguration(conf string, ringSetting int) (*[2][26]int, erro
 		}
 		connections[0][j] = position
 		if connections[1][position] != -1 {
-			return nil, fmt.Errorf("Duplicate Character in map, position: %v", i)
+			return nil, fmt.Errorf("%w: duplicate character in map, position: %v", ErrInvalidWiring, i)
 		}
 		connections[1][position] = j
 	}
@@ -167,18 +256,18 @@ func isAllowedCharacter(r rune) bool {
 func GetRotor(name string, position, setting int) (*Rotor, error) {
 	n, err := getNotches(name)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create rotor %s: %v", name, err)
+		return nil, fmt.Errorf("unable to create rotor %s: %w", name, err)
 	}
 	c, err := getRotor(name)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create rotor %s: %v", name, err)
+		return nil, fmt.Errorf("unable to create rotor %s: %w", name, err)
 	}
 	return newRotor(&RotorConfiguration{
-		name:          name,
-		configuration: c,
-		position:      position,
-		ringSetting:   setting,
-		notches:       n,
+		Name:          name,
+		Configuration: c,
+		Position:      position,
+		RingSetting:   setting,
+		Notches:       n,
 	})
 }
 
@@ -186,13 +275,28 @@ func GetRotor(name string, position, setting int) (*Rotor, error) {
 func GetReflector(name string) (*Rotor, error) {
 	r, err := getReflector(name)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create reflector %s: %v", name, err)
+		return nil, fmt.Errorf("unable to create reflector %s: %w", name, err)
+	}
+	return newRotor(&RotorConfiguration{
+		Name:          name,
+		Configuration: r,
+		Position:      0,
+		RingSetting:   0,
+		Notches:       nil,
+	})
+}
+
+// GetEntryWheel takes an entry wheel name and returns the configuration
+func GetEntryWheel(name string) (*Rotor, error) {
+	r, err := getEntryWheel(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create entry wheel %s: %w", name, err)
 	}
 	return newRotor(&RotorConfiguration{
-		name:          name,
-		configuration: r,
-		position:      0,
-		ringSetting:   0,
-		notches:       nil,
+		Name:          name,
+		Configuration: r,
+		Position:      0,
+		RingSetting:   0,
+		Notches:       nil,
 	})
 }
@@ -1,6 +1,7 @@
 package enigma
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -139,3 +140,133 @@ func TestTraverse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSetting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{
+			name:     "letter",
+			input:    "A",
+			expected: 0,
+		}, {
+			name:     "last letter",
+			input:    "Z",
+			expected: 25,
+		}, {
+			name:     "number",
+			input:    "1",
+			expected: 0,
+		}, {
+			name:     "padded number",
+			input:    "06",
+			expected: 5,
+		}, {
+			name:     "last number",
+			input:    "26",
+			expected: 25,
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res, err := ParseSetting(tt.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res, "setting should match")
+		})
+	}
+}
+
+func TestInvalidParseSetting(t *testing.T) {
+	tests := []string{"", "0", "27", "a", "AB", "-1"}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseSetting(tt)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseRotorConfiguration(t *testing.T) {
+	c, err := ParseRotorConfiguration(RotorIII, "F", "07")
+	assert.Nil(t, err)
+	assert.Equal(t, &RotorConfiguration{
+		Name:          RotorIII,
+		Configuration: rotorIII,
+		Position:      5,
+		RingSetting:   6,
+		Notches:       []int{21},
+	}, c, "configuration should match")
+
+	_, err = ParseRotorConfiguration(RotorIII, "F", "27")
+	assert.Error(t, err)
+	_, err = ParseRotorConfiguration("RotorX", "F", "1")
+	assert.Error(t, err)
+}
+
+func TestFillRotorConfiguration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *RotorConfiguration
+		expected *RotorConfiguration
+	}{
+		{
+			name:     "named",
+			input:    &RotorConfiguration{Name: RotorIII},
+			expected: &RotorConfiguration{Name: RotorIII, Configuration: rotorIII, Notches: []int{21}},
+		}, {
+			name:     "wiring given",
+			input:    &RotorConfiguration{Name: RotorIII, Configuration: rotorIII},
+			expected: &RotorConfiguration{Name: RotorIII, Configuration: rotorIII, Notches: []int{21}},
+		}, {
+			name:     "notches given",
+			input:    &RotorConfiguration{Name: RotorIII, Notches: []int{4}},
+			expected: &RotorConfiguration{Name: RotorIII, Configuration: rotorIII, Notches: []int{4}},
+		}, {
+			name:     "custom",
+			input:    &RotorConfiguration{Name: "custom", Configuration: rotorI},
+			expected: &RotorConfiguration{Name: "custom", Configuration: rotorI},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c, err := fillRotorConfiguration(tt.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, c, "configuration should match")
+		})
+	}
+}
+
+func TestInvalidNewRotor(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration string
+	}{
+		{
+			name:          "short",
+			configuration: "ABC",
+		}, {
+			name:          "repeated",
+			configuration: "AACDEFGHIJKLMNOPQRSTUVWXYZ",
+		}, {
+			name:          "lower case",
+			configuration: "abcdefghijklmnopqrstuvwxyz",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := newRotor(&RotorConfiguration{Name: "custom", Configuration: tt.configuration})
+			assert.Nil(t, r)
+			assert.True(t, errors.Is(err, ErrInvalidWiring), "%v should be invalid wiring", err)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package enigma
+
+import (
+	"fmt"
+)
+
+// Snapshot is the state of the moving parts of a machine, from which it can be restored
+type Snapshot struct {
+	// Positions of the rotors, from right to left as taken by New
+	Positions []int
+	// Reflector is the position of the reflector, which moves on machines such as the Enigma G
+	Reflector int
+}
+
+// Snapshot captures the current positions of the rotors and reflector
+func (e *Enigma) Snapshot() Snapshot {
+	s := Snapshot{Reflector: e.reflector.position}
+	for _, r := range e.rotors {
+		s.Positions = append(s.Positions, r.position)
+	}
+	return s
+}
+
+// Restore returns the rotors and reflector to the positions captured by a snapshot of this machine
+func (e *Enigma) Restore(s Snapshot) error {
+	if len(s.Positions) != len(e.rotors) {
+		return fmt.Errorf("%w: snapshot has %d rotor positions for %d rotors", ErrRotorCount, len(s.Positions), len(e.rotors))
+	}
+	for _, p := range s.Positions {
+		if p < 0 || p >= 26 {
+			return &InvalidPositionError{Component: "snapshot", Setting: "rotor position", Position: p}
+		}
+	}
+	if s.Reflector < 0 || s.Reflector >= 26 {
+		return &InvalidPositionError{Component: "snapshot", Setting: "reflector position", Position: s.Reflector}
+	}
+	e.restore(s)
+	return nil
+}
+
+// Reset returns the rotors and reflector to the positions the machine was instantiated with
+func (e *Enigma) Reset() {
+	e.restore(e.start)
+}
+
+func (e *Enigma) restore(s Snapshot) {
+	for i, p := range s.Positions {
+		e.rotors[i].position = p
+	}
+	e.reflector.position = s.Reflector
+}
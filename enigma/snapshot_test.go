@@ -0,0 +1,75 @@
+package enigma
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	k, err := ParseKey("B I-II-III 01-02-07 AXF AZ BC XT")
+	assert.Nil(t, err)
+	e, err := k.Enigma()
+	assert.Nil(t, err)
+	res, err := e.Encode("AAAAA")
+	assert.Nil(t, err)
+	assert.Equal(t, "JTUJZ", res, "encoded message should match")
+
+	s := e.Snapshot()
+	assert.Equal(t, Snapshot{Positions: []int{10, 23, 0}}, s, "snapshot should match")
+	first, err := e.Encode("ENIGMA")
+	assert.Nil(t, err)
+	err = e.Restore(s)
+	assert.Nil(t, err)
+	second, err := e.Encode("ENIGMA")
+	assert.Nil(t, err)
+	assert.Equal(t, first, second, "encoding from a restored snapshot should match")
+
+	e.Reset()
+	res, err = e.Encode("JTUJZ")
+	assert.Nil(t, err)
+	assert.Equal(t, "AAAAA", res, "decoded message should match")
+}
+
+func TestSnapshotReflector(t *testing.T) {
+	conf := []*RotorConfiguration{
+		{Name: RotorGIII, Position: 25, RingSetting: 5},
+		{Name: RotorGII, Position: 24},
+		{Name: RotorGI, Position: 1, RingSetting: 3},
+	}
+	e, err := NewFromModel(ModelG, conf, ReflectorG, "", WithReflectorPosition(4))
+	assert.Nil(t, err)
+	_, err = e.Encode("ABWEHRNACHRICHTENDIENST")
+	assert.Nil(t, err)
+	assert.Equal(t, Snapshot{Positions: []int{22, 7, 8}, Reflector: 9}, e.Snapshot(), "snapshot should match")
+	e.Reset()
+	assert.Equal(t, Snapshot{Positions: []int{25, 24, 1}, Reflector: 4}, e.Snapshot(), "snapshot should match")
+}
+
+func TestInvalidRestore(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot Snapshot
+	}{
+		{
+			name:     "too few rotors",
+			snapshot: Snapshot{Positions: []int{0, 0}},
+		}, {
+			name:     "invalid rotor position",
+			snapshot: Snapshot{Positions: []int{0, 26, 0}},
+		}, {
+			name:     "invalid reflector position",
+			snapshot: Snapshot{Positions: []int{0, 0, 0}, Reflector: -1},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New([]*RotorConfiguration{{Name: RotorIII}, {Name: RotorII}, {Name: RotorI}}, ReflectorB, "")
+			assert.Nil(t, err)
+			err = e.Restore(tt.snapshot)
+			assert.Error(t, err)
+			assert.Equal(t, Snapshot{Positions: []int{0, 0, 0}}, e.Snapshot(), "positions should be unchanged")
+		})
+	}
+}
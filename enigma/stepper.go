@@ -0,0 +1,63 @@
+package enigma
+
+// Stepper is the mechanism that advances the rotors before each key press is enciphered. Rotors are passed as
+// held by the machine, from the fast rotor on the right to the slowest rotor on the left
+type Stepper interface {
+	Step(rotors []*Rotor, reflector *Rotor)
+}
+
+// LeverStepper steps the rotors as done by the M3 and M4 with pawls and levers; i.e. 4th or greater rotor is
+// static and double stepping of the 2nd rotor occurs
+type LeverStepper struct{}
+
+// Step advances the first three rotors, including the double step of the middle rotor
+func (LeverStepper) Step(rotors []*Rotor, _ *Rotor) {
+	cycle1, cycle2 := false, false
+	if rotors[0].isNotchEngaged() {
+		cycle1 = true
+	}
+	if rotors[1].isNotchEngaged() {
+		cycle1 = true
+		cycle2 = true
+	}
+	rotors[0].cycle()
+	if cycle1 {
+		rotors[1].cycle()
+	}
+	if cycle2 {
+		rotors[2].cycle()
+	}
+}
+
+// OdometerStepper steps the rotors as an odometer; a rotor advances only when the rotor to its right steps away
+// from a notch, so there is no double stepping. All rotors take part, the reflector is static
+type OdometerStepper struct{}
+
+// Step advances the fast rotor and carries through every rotor whose neighbour stepped from a notch
+func (OdometerStepper) Step(rotors []*Rotor, _ *Rotor) {
+	carry(rotors)
+}
+
+// CogStepper steps the rotors through cog wheels as done by the Abwehr Enigma G. Stepping is as the odometer,
+// but the carry from the leftmost rotor continues into the reflector, which turns with the rotors
+type CogStepper struct{}
+
+// Step advances the rotors as the odometer and turns the reflector when the leftmost rotor steps from a notch
+func (CogStepper) Step(rotors []*Rotor, reflector *Rotor) {
+	if carry(rotors) && reflector != nil {
+		reflector.cycle()
+	}
+}
+
+// carry steps each rotor in turn while the previous one stepped from a notch, reporting whether the last
+// rotor did so as well
+func carry(rotors []*Rotor) bool {
+	for _, r := range rotors {
+		engaged := r.isNotchEngaged()
+		r.cycle()
+		if !engaged {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package enigma
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name              string
+		stepper           Stepper
+		start             []int
+		steps             int
+		expected          []int
+		expectedReflector int
+	}{
+		{
+			name:     "lever",
+			stepper:  LeverStepper{},
+			start:    []int{0, 0, 0, 0},
+			steps:    1,
+			expected: []int{1, 0, 0, 0},
+		}, {
+			name:     "lever double step",
+			stepper:  LeverStepper{},
+			start:    []int{21, 3, 0, 0},
+			steps:    2,
+			expected: []int{23, 5, 1, 0},
+		}, {
+			name:     "lever static fourth rotor",
+			stepper:  LeverStepper{},
+			start:    []int{21, 4, 16, 0},
+			steps:    1,
+			expected: []int{22, 5, 17, 0},
+		}, {
+			name:     "odometer",
+			stepper:  OdometerStepper{},
+			start:    []int{21, 3, 0, 0},
+			steps:    2,
+			expected: []int{23, 4, 0, 0},
+		}, {
+			name:     "odometer carries to fourth rotor",
+			stepper:  OdometerStepper{},
+			start:    []int{21, 4, 16, 7},
+			steps:    1,
+			expected: []int{22, 5, 17, 8},
+		}, {
+			name:              "cog",
+			stepper:           CogStepper{},
+			start:             []int{21, 3, 0, 0},
+			steps:             2,
+			expected:          []int{23, 4, 0, 0},
+			expectedReflector: 0,
+		}, {
+			name:              "cog turns reflector",
+			stepper:           CogStepper{},
+			start:             []int{21, 4, 16, 7},
+			steps:             1,
+			expected:          []int{22, 5, 17, 8},
+			expectedReflector: 1,
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			notches := []int{21, 4, 16, 7}
+			rotors := []*Rotor{}
+			for i, p := range tt.start {
+				rotors = append(rotors, &Rotor{position: p, notches: map[int]bool{notches[i]: true}})
+			}
+			reflector := &Rotor{}
+			for i := 0; i < tt.steps; i++ {
+				tt.stepper.Step(rotors, reflector)
+			}
+			positions := []int{}
+			for _, r := range rotors {
+				positions = append(positions, r.position)
+			}
+			assert.Equal(t, tt.expected, positions, "rotor positions should match")
+			assert.Equal(t, tt.expectedReflector, reflector.position, "reflector position should match")
+		})
+	}
+}
+
+func TestEncodeWithStepper(t *testing.T) {
+	tests := []struct {
+		name     string
+		stepper  Stepper
+		input    string
+		expected string
+	}{
+		{
+			name:     "lever",
+			stepper:  LeverStepper{},
+			input:    "AAAAA",
+			expected: "EQIBM",
+		}, {
+			name:     "odometer",
+			stepper:  OdometerStepper{},
+			input:    "AAAAA",
+			expected: "EQEZI",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			conf := []*RotorConfiguration{
+				{Name: RotorIII, Position: 20},
+				{Name: RotorII, Position: 3},
+				{Name: RotorI, Position: 0},
+			}
+			e, err := New(conf, ReflectorB, "", WithStepper(tt.stepper))
+			assert.Nil(t, err)
+			res, err := e.Encode(tt.input)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, res, "encoded string should match")
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package enigma
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// streamBufferSize is the size of the buffer a Writer enciphers into before passing on
+const streamBufferSize = 4096
+
+// Writer enciphers bytes with an enigma machine as they are written, passing them on to an underlying writer
+type Writer struct {
+	w   io.Writer
+	e   *Enigma
+	buf []byte
+}
+
+// NewWriter returns a Writer enciphering with the machine, which steps as the bytes are written. Letters are enciphered
+// and upper cased, digits and whitespace are retained as by Encode, and any other byte is an error. A machine with
+// conventions retains nothing, as the conventions need the whole text; prepare it with Prepare first
+func NewWriter(w io.Writer, e *Enigma) *Writer {
+	return &Writer{w: w, e: e, buf: make([]byte, streamBufferSize)}
+}
+
+// Write enciphers p to the underlying writer, stopping at the first byte that cannot be enciphered
+func (w *Writer) Write(p []byte) (int, error) {
+	n, done := 0, 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > len(w.buf) {
+			chunk = chunk[:len(w.buf)]
+		}
+		i, encErr := w.e.encodeBytes(w.buf, chunk)
+		written, err := w.w.Write(w.buf[:i])
+		n += written
+		if err != nil {
+			return n, err
+		}
+		if encErr != nil {
+			return n, &UnencodableCharacterError{Rune: rune(chunk[i]), Offset: done + i}
+		}
+		done += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// Reader enciphers bytes with an enigma machine as they are read from an underlying reader
+type Reader struct {
+	r   io.Reader
+	e   *Enigma
+	err error
+}
+
+// NewReader returns a Reader enciphering with the machine, which steps as the bytes are read. Letters are enciphered
+// and upper cased, digits and whitespace are retained as by Encode, and any other byte is an error. A machine with
+// conventions retains nothing, as by NewWriter
+func NewReader(r io.Reader, e *Enigma) *Reader {
+	return &Reader{r: r, e: e}
+}
+
+// Read reads and enciphers into p, stopping at the first byte that cannot be enciphered
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.r.Read(p)
+	i, encErr := r.e.encodeBytes(p, p[:n])
+	if encErr != nil {
+		r.err = encErr
+		return i, encErr
+	}
+	return n, err
+}
+
+// EncodeBytes enciphers the ASCII bytes of src into dst without allocating, as Encode does for strings, returning
+// the number of bytes enciphered. Enciphering stops at the first byte that cannot be, and dst must be at least as
+// long as src or io.ErrShortBuffer is returned with nothing enciphered. A machine with conventions accepts only
+// letters, as by NewWriter
+func (e *Enigma) EncodeBytes(dst, src []byte) (int, error) {
+	if len(dst) < len(src) {
+		return 0, io.ErrShortBuffer
+	}
+	return e.encodeBytes(dst, src)
+}
+
+// EncodeInPlace enciphers the ASCII bytes of b in place without allocating, as EncodeBytes
+func (e *Enigma) EncodeInPlace(b []byte) (int, error) {
+	return e.encodeBytes(b, b)
+}
+
+// encodeBytes enciphers src into dst, returning the number of bytes enciphered before any byte that cannot be
+func (e *Enigma) encodeBytes(dst, src []byte) (int, error) {
+	for i, b := range src {
+		c, ok := e.encodeByte(b)
+		if !ok {
+			return i, &UnencodableCharacterError{Rune: rune(b), Offset: i}
+		}
+		dst[i] = c
+	}
+	return len(src), nil
+}
+
+// encodeByte enciphers an ASCII letter, passing through the characters retained by Encode unless the machine has
+// conventions, and reports whether the byte could be handled
+func (e *Enigma) encodeByte(b byte) (byte, bool) {
+	if 'a' <= b && b <= 'z' {
+		b -= 'a' - 'A'
+	}
+	if isAllowedCharacter(rune(b)) {
+		return byte(e.encode(rune(b))), true
+	}
+	if b < utf8.RuneSelf && e.conventions == "" && isRetainCharacter(rune(b)) {
+		return b, true
+	}
+	return b, false
+}
@@ -0,0 +1,204 @@
+package enigma
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStreamTestEnigma(t testing.TB) *Enigma {
+	k, err := ParseKey("B I-II-III 01-02-07 AXF AZ BC XT")
+	assert.Nil(t, err)
+	e, err := k.Enigma()
+	assert.Nil(t, err)
+	return e
+}
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "base",
+			input:    "AAAAA",
+			expected: "JTUJZ",
+		}, {
+			name:     "lower case and retained",
+			input:    "aa a\naa1",
+			expected: "JT U\nJZ1",
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := NewWriter(buf, newStreamTestEnigma(t))
+			n, err := w.Write([]byte(tt.input))
+			assert.Nil(t, err)
+			assert.Equal(t, len(tt.input), n)
+			assert.Equal(t, tt.expected, buf.String(), "enciphered output should match")
+		})
+	}
+}
+
+func TestStreamMatchesEncode(t *testing.T) {
+	input := strings.Repeat("THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG 1234\n", 500)
+	expected, err := newStreamTestEnigma(t).Encode(input)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, newStreamTestEnigma(t))
+	for _, line := range strings.SplitAfter(input, "\n") {
+		_, err := w.Write([]byte(line))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, expected, buf.String(), "written output should match Encode")
+
+	res, err := ioutil.ReadAll(NewReader(strings.NewReader(input), newStreamTestEnigma(t)))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(res), "read output should match Encode")
+}
+
+func TestInvalidStream(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf, newStreamTestEnigma(t))
+	n, err := w.Write([]byte("AAA!AA"))
+	assert.Error(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "JTU", buf.String(), "output before the invalid byte should be written")
+
+	r := NewReader(strings.NewReader("AAA!AA"), newStreamTestEnigma(t))
+	res, err := ioutil.ReadAll(r)
+	assert.Error(t, err)
+	assert.Equal(t, "JTU", string(res), "output before the invalid byte should be read")
+	_, err = r.Read(make([]byte, 4))
+	assert.Error(t, err)
+}
+
+func TestStreamConventions(t *testing.T) {
+	e := newStreamTestEnigma(t)
+	assert.Nil(t, WithConventions(ConventionsArmy)(e))
+	buf := &bytes.Buffer{}
+	n, err := NewWriter(buf, e).Write([]byte("UM 1830"))
+	assert.True(t, errors.Is(err, ErrUnencodableCharacter), "space should not pass in the clear: %v", err)
+	assert.Equal(t, 2, n)
+
+	e = newStreamTestEnigma(t)
+	assert.Nil(t, WithConventions(ConventionsNavy)(e))
+	_, err = ioutil.ReadAll(NewReader(strings.NewReader("AA1"), e))
+	assert.True(t, errors.Is(err, ErrUnencodableCharacter), "digit should not pass in the clear: %v", err)
+
+	e = newStreamTestEnigma(t)
+	assert.Nil(t, WithConventions(ConventionsArmy)(e))
+	n, err = e.EncodeBytes(make([]byte, 3), []byte("AA1"))
+	assert.True(t, errors.Is(err, ErrUnencodableCharacter), "digit should not pass in the clear: %v", err)
+	assert.Equal(t, 2, n)
+	n, err = e.EncodeInPlace([]byte("A A"))
+	assert.True(t, errors.Is(err, ErrUnencodableCharacter), "space should not pass in the clear: %v", err)
+	assert.Equal(t, 1, n)
+}
+
+func TestEncodeBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		dst      int
+		expected string
+		n        int
+		err      error
+	}{
+		{
+			name:     "base",
+			input:    "aa a\naa1",
+			dst:      8,
+			expected: "JT U\nJZ1",
+			n:        8,
+		}, {
+			name:     "longer destination",
+			input:    "AAAAA",
+			dst:      10,
+			expected: "JTUJZ",
+			n:        5,
+		}, {
+			name:     "unencodable",
+			input:    "AA?AA",
+			dst:      5,
+			expected: "JT",
+			n:        2,
+			err:      ErrUnencodableCharacter,
+		}, {
+			name:  "short destination",
+			input: "AAAAA",
+			dst:   4,
+			err:   io.ErrShortBuffer,
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			dst := make([]byte, tt.dst)
+			n, err := newStreamTestEnigma(t).EncodeBytes(dst, []byte(tt.input))
+			assert.True(t, errors.Is(err, tt.err), "error should match: %v", err)
+			assert.Equal(t, tt.n, n)
+			assert.Equal(t, tt.expected, string(dst[:n]), "encoded bytes should match")
+
+			b := []byte(tt.input)
+			n, err = newStreamTestEnigma(t).EncodeInPlace(b)
+			if tt.err != io.ErrShortBuffer {
+				assert.True(t, errors.Is(err, tt.err), "error should match: %v", err)
+				assert.Equal(t, tt.n, n)
+				assert.Equal(t, tt.expected, string(b[:n]), "encoded bytes should match in place")
+			}
+		})
+	}
+}
+
+func TestEncodeBytesAllocations(t *testing.T) {
+	e := newStreamTestEnigma(t)
+	b := []byte(strings.Repeat("HELLO WORLD ", 100))
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = e.EncodeInPlace(b)
+	})
+	assert.Equal(t, 0.0, allocs, "encoding bytes should not allocate")
+}
+
+func BenchmarkEncodeBytes(b *testing.B) {
+	e := newStreamTestEnigma(b)
+	src := []byte(strings.Repeat("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 40))
+	dst := make([]byte, len(src))
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = e.EncodeBytes(dst, src)
+	}
+}
+
+func BenchmarkEncodeInPlace(b *testing.B) {
+	e := newStreamTestEnigma(b)
+	buf := []byte(strings.Repeat("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 40))
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = e.EncodeInPlace(buf)
+	}
+}
+
+func BenchmarkEncodeString(b *testing.B) {
+	e := newStreamTestEnigma(b)
+	s := strings.Repeat("ABCDEFGHIJKLMNOPQRSTUVWXYZ", 40)
+	b.SetBytes(int64(len(s)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = e.Encode(s)
+	}
+}
@@ -0,0 +1,62 @@
+package enigma
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Trace records the letter after each stage of the signal path for a single key press, and the positions of the
+// rotors and reflector either side of stepping
+type Trace struct {
+	Input      rune
+	Plugboard  rune
+	EntryWheel rune
+	// Forward holds the letter after each rotor on the way to the reflector, from right to left
+	Forward   []rune
+	Reflector rune
+	// Backward holds the letter after each rotor on the way back from the reflector, from left to right
+	Backward         []rune
+	EntryWheelReturn rune
+	Output           rune
+	Before           Snapshot
+	After            Snapshot
+}
+
+// EncodeTrace encodes a single letter as Encode does, cycling the machine, and returns the trace of its signal path.
+// Characters that are not letters are returned unchanged, without cycling the machine, with an otherwise empty trace.
+// A machine with conventions has no key for them and returns 0, so that nothing passes in the clear
+func (e *Enigma) EncodeTrace(r rune) (rune, Trace) {
+	t := Trace{Input: r}
+	c := unicode.ToUpper(r)
+	if !isAllowedCharacter(c) {
+		if e.conventions == "" {
+			t.Output = r
+		}
+		return t.Output, t
+	}
+	t.Output = e.encodeTrace(c, &t)
+	return t.Output, t
+}
+
+// String describes the signal path of the trace on a single line
+func (t Trace) String() string {
+	if t.Forward == nil && t.Output == 0 {
+		return fmt.Sprintf("%c has no key", t.Input)
+	}
+	if t.Forward == nil {
+		return fmt.Sprintf("%c retained", t.Input)
+	}
+	return fmt.Sprintf("%c > plugboard %c > entry %c > rotors %s > reflector %c > rotors %s > entry %c > plugboard %c, positions %s > %s",
+		t.Input, t.Plugboard, t.EntryWheel, string(t.Forward), t.Reflector, string(t.Backward), t.EntryWheelReturn, t.Output,
+		windows(t.Before.Positions), windows(t.After.Positions))
+}
+
+// windows formats rotor positions, given from right to left, as the letters seen in the windows from left to right
+func windows(positions []int) string {
+	var b strings.Builder
+	for i := len(positions) - 1; i >= 0; i-- {
+		b.WriteRune(rune(positions[i]) + runeOffset)
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package enigma
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeTrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		input    rune
+		expected Trace
+	}{
+		{
+			name:  "base",
+			key:   "B I-II-III 01-01-01 AAA",
+			input: 'A',
+			expected: Trace{
+				Input:            'A',
+				Plugboard:        'A',
+				EntryWheel:       'A',
+				Forward:          []rune{'C', 'D', 'F'},
+				Reflector:        'S',
+				Backward:         []rune{'S', 'E', 'B'},
+				EntryWheelReturn: 'B',
+				Output:           'B',
+				Before:           Snapshot{Positions: []int{0, 0, 0}},
+				After:            Snapshot{Positions: []int{1, 0, 0}},
+			},
+		}, {
+			name:  "plugboard",
+			key:   "B I-II-III 01-01-01 AAA AZ BX",
+			input: 'z',
+			expected: Trace{
+				Input:            'z',
+				Plugboard:        'A',
+				EntryWheel:       'A',
+				Forward:          []rune{'C', 'D', 'F'},
+				Reflector:        'S',
+				Backward:         []rune{'S', 'E', 'B'},
+				EntryWheelReturn: 'B',
+				Output:           'X',
+				Before:           Snapshot{Positions: []int{0, 0, 0}},
+				After:            Snapshot{Positions: []int{1, 0, 0}},
+			},
+		}, {
+			name:  "retained",
+			key:   "B I-II-III 01-01-01 AAA",
+			input: '4',
+			expected: Trace{
+				Input:  '4',
+				Output: '4',
+			},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseKey(tt.key)
+			assert.Nil(t, err)
+			e, err := k.Enigma()
+			assert.Nil(t, err)
+			res, trace := e.EncodeTrace(tt.input)
+			assert.Equal(t, tt.expected.Output, res, "output should match")
+			assert.Equal(t, tt.expected, trace, "trace should match")
+		})
+	}
+}
+
+func TestEncodeTraceMatchesEncode(t *testing.T) {
+	k, err := ParseKey("B II-IV-V 02-21-12 BLA AV BS CG DL FU HZ IN KM OW RX")
+	assert.Nil(t, err)
+	e, err := k.Enigma()
+	assert.Nil(t, err)
+	var b strings.Builder
+	for _, r := range "EDPUDNRGYSZRCXNUYTPOMRMBOFKTB" {
+		res, trace := e.EncodeTrace(r)
+		assert.Equal(t, trace.After, e.Snapshot(), "trace should end at the machine positions")
+		b.WriteRune(res)
+	}
+	assert.Equal(t, "AUFKLXABTEILUNGXVONXKURTINOWA", b.String(), "traced output should match Encode")
+}
+
+func TestTraceString(t *testing.T) {
+	k, err := ParseKey("B I-II-III 01-01-01 AAA")
+	assert.Nil(t, err)
+	e, err := k.Enigma()
+	assert.Nil(t, err)
+	_, trace := e.EncodeTrace('A')
+	assert.Equal(t, "A > plugboard A > entry A > rotors CDF > reflector S > rotors SEB > entry B > plugboard B, positions AAA > AAB", trace.String())
+}
+
+func TestTraceConventions(t *testing.T) {
+	k, err := ParseKey("B I-II-III 01-01-01 AAA")
+	assert.Nil(t, err)
+	e, err := k.Enigma(WithConventions(ConventionsArmy))
+	assert.Nil(t, err)
+	before := e.Snapshot()
+	res, trace := e.EncodeTrace('1')
+	assert.Equal(t, rune(0), res, "digit should not pass in the clear")
+	assert.Equal(t, "1 has no key", trace.String())
+	assert.Equal(t, before, e.Snapshot(), "machine should not step")
+}
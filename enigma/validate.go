@@ -0,0 +1,152 @@
+package enigma
+
+import "fmt"
+
+// Problem is a fault found in a configuration, with the field at fault named as in configuration files, such as
+// "rotors[1].ringSetting" or "plugboard", the rotors counting from the left
+type Problem struct {
+	Field string
+	Err   error
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %v", p.Field, p.Err)
+}
+
+// Validate checks the whole configuration before anything is built, reporting every problem found rather than
+// stopping at the first as Config.Enigma does. A rotor fitted more than once is a problem even without a model,
+// as no machine could have it. The report is empty if the configuration is valid
+func Validate(c *Config) []Problem {
+	if c == nil {
+		return []Problem{{Field: "", Err: ErrMissingConfig}}
+	}
+	problems := []Problem{}
+	confs := c.rightToLeft()
+	if len(confs) < 3 && c.Model == "" {
+		problems = append(problems, Problem{
+			Field: "rotors",
+			Err:   fmt.Errorf("%w: at least 3 required: %d", ErrRotorCount, len(confs)),
+		})
+	}
+	for i, r := range confs {
+		problems = append(problems, rotorProblems(confs, i, r)...)
+	}
+	problems = append(problems, duplicateProblems(confs)...)
+	problems = append(problems, reflectorProblems(c.Reflector)...)
+	if c.EntryWheel != "" {
+		if _, err := getEntryWheel(c.EntryWheel); err != nil {
+			problems = append(problems, Problem{Field: "entryWheel", Err: err})
+		}
+	}
+	if c.Stepper != "" {
+		if _, err := getStepper(c.Stepper); err != nil {
+			problems = append(problems, Problem{Field: "stepper", Err: err})
+		}
+	}
+	pairs, err := parseStringPlugboard(c.Plugboard)
+	if err == nil {
+		_, err = newPlugboard(pairs)
+	}
+	if err != nil {
+		problems = append(problems, Problem{Field: "plugboard", Err: err})
+	}
+	if c.Model == "" {
+		return problems
+	}
+	m, err := GetModel(c.Model)
+	if err != nil {
+		return append(problems, Problem{Field: "model", Err: err})
+	}
+	reflector := ""
+	if c.Reflector != nil {
+		reflector = c.Reflector.Name
+		if !m.ReflectorSettable && c.Reflector.Position != 0 {
+			problems = append(problems, Problem{
+				Field: "reflector.position",
+				Err:   fmt.Errorf("%w: the %s has no settable reflector", ErrModelMismatch, m.Name),
+			})
+		}
+	}
+	return append(problems, m.problems(confs, reflector, c.Plugboard)...)
+}
+
+// rotorProblems checks a rotor, given at index i of the rotors from right to left, with the checks of newRotor
+func rotorProblems(confs []*RotorConfiguration, i int, r *RotorConfiguration) []Problem {
+	problems := []Problem{}
+	if r == nil {
+		return append(problems, Problem{Field: fmt.Sprintf("rotors[%d]", len(confs)-1-i), Err: ErrMissingRotor})
+	}
+	filled, err := fillRotorConfiguration(r)
+	if err != nil {
+		return append(problems, Problem{Field: rotorField(confs, i, "name"), Err: err})
+	}
+	if err := validateWiring(filled.Configuration); err != nil {
+		problems = append(problems, Problem{Field: rotorField(confs, i, "configuration"), Err: err})
+	}
+	if err := checkPosition("rotor "+r.Name, "start position", r.Position); err != nil {
+		problems = append(problems, Problem{Field: rotorField(confs, i, "position"), Err: err})
+	}
+	if err := checkPosition("rotor "+r.Name, "ring setting", r.RingSetting); err != nil {
+		problems = append(problems, Problem{Field: rotorField(confs, i, "ringSetting"), Err: err})
+	}
+	for _, n := range filled.Notches {
+		if err := checkPosition("rotor "+r.Name, "notch position", n); err != nil {
+			problems = append(problems, Problem{Field: rotorField(confs, i, "notches"), Err: err})
+		}
+	}
+	return problems
+}
+
+// duplicateProblems reports each rotor, given from right to left, whose name was already fitted further right
+func duplicateProblems(confs []*RotorConfiguration) []Problem {
+	problems := []Problem{}
+	used := map[string]bool{}
+	for i, r := range confs {
+		if r == nil {
+			continue
+		}
+		if used[r.Name] {
+			problems = append(problems, Problem{
+				Field: rotorField(confs, i, "name"),
+				Err:   fmt.Errorf("%w: %s", ErrDuplicateRotor, r.Name),
+			})
+		}
+		used[r.Name] = true
+	}
+	return problems
+}
+
+// reflectorProblems checks the reflector is known, or for a rewired UKW-D that its pairs are valid, and its position
+func reflectorProblems(r *ReflectorConfig) []Problem {
+	if r == nil {
+		return []Problem{{Field: "reflector", Err: ErrNoReflector}}
+	}
+	problems := []Problem{}
+	switch {
+	case r.Wiring != "" && r.Name != ReflectorD:
+		problems = append(problems, Problem{
+			Field: "reflector.wiring",
+			Err:   fmt.Errorf("%w: reflector %s cannot be rewired", ErrInvalidWiring, r.Name),
+		})
+	case r.Wiring != "":
+		if _, err := NewReflectorD(r.Wiring, NotationBletchley); err != nil {
+			problems = append(problems, Problem{Field: "reflector.wiring", Err: err})
+		}
+	case r.Name == "":
+		problems = append(problems, Problem{Field: "reflector.name", Err: ErrNoReflector})
+	default:
+		if _, err := getReflector(r.Name); err != nil {
+			problems = append(problems, Problem{Field: "reflector.name", Err: err})
+		}
+	}
+	if err := checkPosition("reflector "+r.Name, "reflector position", r.Position); err != nil {
+		problems = append(problems, Problem{Field: "reflector.position", Err: err})
+	}
+	return problems
+}
+
+// rotorField names a field of the rotor at index i of the rotors from right to left, as indexed in configuration
+// files from left to right
+func rotorField(confs []*RotorConfiguration, i int, field string) string {
+	return fmt.Sprintf("rotors[%d].%s", len(confs)-1-i, field)
+}
@@ -0,0 +1,155 @@
+package enigma
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		expected map[string]error
+	}{
+		{
+			name: "valid",
+			config: &Config{
+				Model:     ModelM3,
+				Rotors:    []*RotorConfiguration{{Name: RotorII, Position: 1, RingSetting: 1}, {Name: RotorIV}, {Name: RotorV}},
+				Reflector: &ReflectorConfig{Name: ReflectorB},
+				Plugboard: "AV BS CG DL FU HZ IN KM OW RX",
+			},
+			expected: map[string]error{},
+		}, {
+			name: "every field",
+			config: &Config{
+				Rotors: []*RotorConfiguration{
+					{Name: "RotorX"},
+					{Name: RotorIV, Position: 26, RingSetting: -1},
+					{Name: RotorIV},
+				},
+				Reflector:  &ReflectorConfig{Name: ReflectorB, Position: 30},
+				EntryWheel: "EntryWheelX",
+				Stepper:    "Ratchet",
+				Plugboard:  "AB BC",
+			},
+			expected: map[string]error{
+				"rotors[0].name":        ErrUnknownComponent,
+				"rotors[1].position":    ErrInvalidPosition,
+				"rotors[1].ringSetting": ErrInvalidPosition,
+				"rotors[1].name":        ErrDuplicateRotor,
+				"reflector.position":    ErrInvalidPosition,
+				"entryWheel":            ErrUnknownComponent,
+				"stepper":               ErrUnknownComponent,
+				"plugboard":             ErrRepeatedLetter,
+			},
+		}, {
+			name: "model",
+			config: &Config{
+				Model:     ModelD,
+				Rotors:    []*RotorConfiguration{{Name: RotorDI}, {Name: RotorII}, {Name: RotorDIII, RingSetting: 2}},
+				Reflector: &ReflectorConfig{Name: ReflectorB, Position: 4},
+				Plugboard: "AZ",
+			},
+			expected: map[string]error{
+				"rotors[1].name": ErrModelMismatch,
+				"reflector.name": ErrModelMismatch,
+				"plugboard":      ErrModelMismatch,
+			},
+		}, {
+			name: "unknown model",
+			config: &Config{
+				Model:     "EnigmaZ",
+				Rotors:    []*RotorConfiguration{{Name: RotorI}, {Name: RotorII}, {Name: RotorIII}},
+				Reflector: &ReflectorConfig{Name: ReflectorB},
+			},
+			expected: map[string]error{
+				"model": ErrUnknownComponent,
+			},
+		}, {
+			name: "missing",
+			config: &Config{
+				Rotors: []*RotorConfiguration{{Name: RotorI}},
+			},
+			expected: map[string]error{
+				"rotors":    ErrRotorCount,
+				"reflector": ErrNoReflector,
+			},
+		}, {
+			name: "missing rotor",
+			config: &Config{
+				Model:     ModelM3,
+				Rotors:    []*RotorConfiguration{{Name: RotorI}, nil, {Name: RotorI}},
+				Reflector: &ReflectorConfig{Name: ReflectorB},
+			},
+			expected: map[string]error{
+				"rotors[1]":      ErrMissingRotor,
+				"rotors[0].name": ErrDuplicateRotor,
+			},
+		}, {
+			name: "short wiring",
+			config: &Config{
+				Rotors:    []*RotorConfiguration{{Name: "X", Configuration: "ABC"}, {Name: RotorII}, {Name: RotorIII}},
+				Reflector: &ReflectorConfig{Name: ReflectorB},
+			},
+			expected: map[string]error{
+				"rotors[0].configuration": ErrInvalidWiring,
+			},
+		}, {
+			name: "rewired reflector",
+			config: &Config{
+				Rotors:    []*RotorConfiguration{{Name: RotorI}, {Name: RotorII}, {Name: RotorIII}},
+				Reflector: &ReflectorConfig{Name: ReflectorD, Wiring: "AC BO DE FG HI KL MN PQ RS TU VW XZ ZZ"},
+			},
+			expected: map[string]error{
+				"reflector.wiring": ErrInvalidPlugboard,
+			},
+		},
+	}
+	for _, test := range tests {
+		tt := test
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			problems := Validate(tt.config)
+			fields := map[string]error{}
+			for _, p := range problems {
+				fields[p.Field] = p.Err
+			}
+			assert.Equal(t, len(tt.expected), len(problems), "%v", problems)
+			for field, expected := range tt.expected {
+				assert.True(t, errors.Is(fields[field], expected), "%s should be %v: %v", field, expected, fields[field])
+			}
+		})
+	}
+}
+
+func TestValidateMatchesEnigma(t *testing.T) {
+	c := &Config{
+		Rotors:    []*RotorConfiguration{{Name: RotorI, RingSetting: 26}, {Name: RotorII}, {Name: RotorIII}},
+		Reflector: &ReflectorConfig{Name: ReflectorB},
+	}
+	problems := Validate(c)
+	assert.Equal(t, 1, len(problems))
+	_, err := c.Enigma()
+	assert.Equal(t, problems[0].Err.Error(), errors.Unwrap(err).Error(), "problem should be the error of Enigma")
+}
+
+func TestMissingRotorConfig(t *testing.T) {
+	input := `{"rotors": [{"name": "RotorI"}, null, {"name": "RotorIII"}], "reflector": {"name": "ReflectorB"}}`
+	e, err := LoadConfig(strings.NewReader(input))
+	assert.Nil(t, e)
+	assert.True(t, errors.Is(err, ErrMissingRotor), "%v should be missing rotor", err)
+}
+
+func TestValidateNilConfig(t *testing.T) {
+	assert.NotPanics(t, func() {
+		assert.Equal(t, []Problem{{Field: "", Err: ErrMissingConfig}}, Validate(nil))
+	})
+	var c *Config
+	e, err := c.Enigma()
+	assert.Nil(t, e)
+	assert.True(t, errors.Is(err, ErrMissingConfig), "%v should be missing configuration", err)
+}